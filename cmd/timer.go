@@ -7,26 +7,19 @@ import (
 	"time"
 )
 
-type Birthday struct {
-	schedule.Schedulable
-}
+func main() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
 
-func (b Birthday) DueTime() time.Time {
-	return time.Now().Add(time.Second * 5)
-}
+	scheduler := schedule.NewTimeScheduler(ctx, time.Second*2, 10)
+	scheduler.Schedule("birthday!", time.Now().Add(time.Second*5), "Happy birthday!")
+	scheduler.Schedule("dentist", time.Now().Add(time.Second*9), "Time to go to the dentist")
 
-func (b Birthday) Id() string {
-	return "birthday!"
-}
+	fmt.Println("DUMPING!")
+	scheduler.Dump()
+	fmt.Println("----")
 
-func main() {
-	ctx := context.Background()
-	scheduler := schedule.NewScheduler[Birthday](ctx, time.Second*2, 10)
-	scheduler.AddReminder(Birthday{})
-	for {
-		fmt.Println("DUMPING!")
-		scheduler.Dump()
-		fmt.Println("----")
-		time.Sleep(5 * time.Second)
+	for reminder := range scheduler.Start() {
+		fmt.Printf("%s: %v\n", reminder.ID, reminder.Data)
 	}
 }
@@ -0,0 +1,136 @@
+package schedule
+
+import "time"
+
+// AckToken identifies the items returned by a call to DuePeek. Each is
+// recorded by its sequence number and the due time it was handed out at, so
+// that a recurring item that has since fired isn't acknowledged for its next
+// occurrence.
+type AckToken struct {
+	seqs map[uint64]time.Time
+}
+
+// DuePeek returns the items that are due without removing them, along with a
+// token that Ack uses to remove them once they have been processed. By default
+// there is no visibility timeout: until they are acknowledged the same items
+// are returned by every DuePeek (and by Due), so a consumer that crashes before
+// calling Ack sees them again. WithAckTimeout hides them from DuePeek for a
+// while instead.
+func (s *Scheduler[T]) DuePeek() ([]T, AckToken) {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	if !s.delivering() {
+		return make([]T, 0), AckToken{}
+	}
+
+	now := s.now()
+	head := s.buckets[0]
+
+	expired := head.removeWhere(func(e *entry[T]) bool {
+		return !e.due.After(now) && e.expired(now) && !e.held
+	})
+	for _, entity := range expired {
+		s.expire(entity)
+	}
+	s.signalIfEmpty()
+
+	due := make([]*entry[T], 0)
+	for _, entity := range head.elements {
+		if entity.due.After(now) {
+			break
+		}
+		if !entity.held && !s.inFlight(entity, now) {
+			due = append(due, entity)
+		}
+	}
+	sortForDelivery(s.order, due)
+
+	if s.ackTimeout > 0 {
+		for _, entity := range due {
+			s.inflight[entity.seq] = now.Add(s.ackTimeout)
+		}
+	}
+
+	token := AckToken{seqs: make(map[uint64]time.Time, len(due))}
+	for _, entity := range due {
+		token.seqs[entity.seq] = entity.due
+	}
+
+	return items(due), token
+}
+
+// Ack removes the items identified by tok that are still scheduled for the
+// occurrence DuePeek returned, counting them as fired, and returns how many
+// it removed. Items that have since been delivered some other way, including
+// recurring items now waiting for a later occurrence, are left alone, so a
+// late or repeated Ack does nothing.
+func (s *Scheduler[T]) Ack(tok AckToken) int {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	now := s.now()
+	acked := make([]*entry[T], 0, len(tok.seqs))
+	for _, bucket := range s.stores() {
+		acked = append(acked, bucket.removeWhere(func(e *entry[T]) bool {
+			due, ok := tok.seqs[e.seq]
+			return ok && e.due.Equal(due) && !e.due.After(now)
+		})...)
+	}
+
+	s.fire(acked)
+	s.signalIfEmpty()
+
+	return len(acked)
+}
+
+// WithAckTimeout gives DuePeek a visibility timeout: the items it returns are
+// in flight, and left out of later DuePeek calls, until they are acknowledged
+// or d has passed on the scheduler's clock, after which they are returned
+// again. That makes for at-least-once delivery to consumers that may crash
+// before calling Ack. Due is unaffected.
+func WithAckTimeout[T Schedulable](d time.Duration) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.ackTimeout = d
+		s.inflight = make(map[uint64]time.Time)
+	}
+}
+
+// inFlight reports whether entity was handed out by DuePeek and is still
+// waiting to be acknowledged. In-flight entries are tracked by their sequence
+// number rather than Id, since Ids need not be unique.
+func (s *Scheduler[T]) inFlight(entity *entry[T], now time.Time) bool {
+	deadline, ok := s.inflight[entity.seq]
+	if !ok {
+		return false
+	}
+	if !now.Before(deadline) {
+		delete(s.inflight, entity.seq)
+		return false
+	}
+	return true
+}
+
+// InFlight returns how many items handed out by DuePeek are waiting to be
+// acknowledged and haven't timed out yet. It is always zero without
+// WithAckTimeout.
+func (s *Scheduler[T]) InFlight() int {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	now := s.now()
+	for seq, deadline := range s.inflight {
+		if !now.Before(deadline) {
+			delete(s.inflight, seq)
+		}
+	}
+	return len(s.inflight)
+}
+
+// landed forgets that entity is in flight once it has been delivered or has
+// otherwise left the scheduler, since it will never be acknowledged.
+func (s *Scheduler[T]) landed(entity *entry[T]) {
+	delete(s.inflight, entity.seq)
+}
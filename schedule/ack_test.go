@@ -0,0 +1,149 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDuePeekAck(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(100 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(200 * time.Millisecond)})
+	clock.Advance(500 * time.Millisecond)
+
+	// A consumer that crashes before acknowledging sees the same items again
+	first, _ := s.DuePeek()
+	second, tok := s.DuePeek()
+	if len(first) != 2 || len(second) != 2 {
+		t.Fatalf("DuePeek() returned %d then %d items, want 2 both times", len(first), len(second))
+	}
+
+	s.AddReminder(testItem{id: "c", due: start.Add(300 * time.Millisecond)})
+	if !s.Cancel("b") {
+		t.Fatal("Cancel(b) = false")
+	}
+
+	if n := s.Ack(tok); n != 1 {
+		t.Errorf("Ack() = %d, want 1 since b was cancelled", n)
+	}
+	if n := s.Ack(tok); n != 0 {
+		t.Errorf("second Ack() = %d, want 0", n)
+	}
+
+	remaining, _ := s.DuePeek()
+	if len(remaining) != 1 || remaining[0].id != "c" {
+		t.Errorf("DuePeek() after Ack = %v, want [c]", remaining)
+	}
+	if s.TotalFired() != 1 {
+		t.Errorf("TotalFired() = %d, want 1", s.TotalFired())
+	}
+}
+
+func TestDuePeekAckTimeout(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock), WithAckTimeout[testItem](2*time.Second))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(100 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(200 * time.Millisecond)})
+	clock.Advance(500 * time.Millisecond)
+
+	// The consumer takes both items and crashes before acknowledging them
+	first, _ := s.DuePeek()
+	if len(first) != 2 {
+		t.Fatalf("DuePeek() returned %d items, want 2", len(first))
+	}
+
+	// While they are in flight nobody else sees them
+	clock.Advance(time.Second)
+	if again, _ := s.DuePeek(); len(again) != 0 {
+		t.Errorf("DuePeek() inside the timeout = %v, want nothing", again)
+	}
+	if n := s.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2 while in flight", n)
+	}
+
+	// Once the timeout passes they are redelivered
+	clock.Advance(time.Second)
+	redelivered, tok := s.DuePeek()
+	if len(redelivered) != 2 || redelivered[0].id != "a" || redelivered[1].id != "b" {
+		t.Fatalf("DuePeek() after the timeout = %v, want [a b]", redelivered)
+	}
+
+	if n := s.Ack(tok); n != 2 {
+		t.Errorf("Ack() = %d, want 2", n)
+	}
+	clock.Advance(5 * time.Second)
+	if after, _ := s.DuePeek(); len(after) != 0 {
+		t.Errorf("DuePeek() after Ack = %v, want nothing", after)
+	}
+}
+
+func TestInFlightCleared(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock), WithAckTimeout[testItem](time.Hour))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(100 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(200 * time.Millisecond)})
+	clock.Advance(500 * time.Millisecond)
+
+	s.DuePeek()
+	if n := s.InFlight(); n != 2 {
+		t.Fatalf("InFlight() = %d after DuePeek, want 2", n)
+	}
+
+	// Items taken some other way will never be acknowledged
+	s.Cancel("a")
+	if n := s.InFlight(); n != 1 {
+		t.Errorf("InFlight() = %d after cancelling one, want 1", n)
+	}
+
+	// Stopping the dispatch loop doesn't affect DuePeek consumers
+	s.Stop()
+	if again, _ := s.DuePeek(); len(again) != 0 {
+		t.Errorf("DuePeek() after Stop = %v, want b still in flight", again)
+	}
+	s.Due()
+	if n := s.InFlight(); n != 0 {
+		t.Errorf("InFlight() = %d after Due, want 0", n)
+	}
+}
+
+func TestAckStaleRecurring(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[recurringItem](context.Background(), time.Second, 4,
+		WithClock[recurringItem](clock), WithAckTimeout[recurringItem](2*time.Second))
+
+	s.AddReminder(recurringItem{testItem{id: "r", due: start.Add(500 * time.Millisecond)}, time.Minute, 0})
+	clock.Advance(time.Second)
+
+	// The first consumer is too slow, so the item is redelivered and acked
+	_, stale := s.DuePeek()
+	clock.Advance(3 * time.Second)
+	_, tok := s.DuePeek()
+	if n := s.Ack(tok); n != 1 {
+		t.Fatalf("Ack() = %d, want 1", n)
+	}
+
+	// The late ack mustn't take the next occurrence with it
+	if n := s.Ack(stale); n != 0 {
+		t.Errorf("stale Ack() = %d, want 0", n)
+	}
+	if n := s.Ack(tok); n != 0 {
+		t.Errorf("repeated Ack() = %d, want 0", n)
+	}
+	if fired := s.TotalFired(); fired != 1 {
+		t.Errorf("TotalFired() = %d, want 1", fired)
+	}
+	if d, ok := s.TimeUntil("r"); !ok || d != 56500*time.Millisecond {
+		t.Errorf("next occurrence due in %s, want 56.5s", d)
+	}
+}
@@ -0,0 +1,156 @@
+package schedule
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// Codec converts items to and from the payload stored alongside their id and
+// due time by MarshalBinary.
+type Codec[T Schedulable] interface {
+	// Encode returns whatever, beyond its id and due time, is needed to
+	// rebuild item.
+	Encode(item T) ([]byte, error)
+	// Decode rebuilds an item due at due from its id and payload.
+	Decode(id string, due time.Time, payload []byte) (T, error)
+}
+
+// WithCodec sets the codec MarshalBinary and UnmarshalBinary use for item
+// payloads.
+func WithCodec[T Schedulable](codec Codec[T]) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.codec = codec
+	}
+}
+
+// binaryVersion is the first byte of the MarshalBinary format.
+const binaryVersion = 1
+
+// MarshalBinary encodes every scheduled item, in due-time order, in a compact
+// length-prefixed format: each item's id, due time in Unix nanoseconds and
+// the payload from the WithCodec codec. Only that is kept; the rest of an
+// item's state, such as how often a recurring item has fired, is not.
+func (s *Scheduler[T]) MarshalBinary() ([]byte, error) {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	if s.codec == nil {
+		return nil, ErrNoCodec
+	}
+
+	entries := s.entries()
+	sortByDue(entries)
+
+	buf := make([]byte, 0, 1+binary.MaxVarintLen64+len(entries)*24)
+	buf = append(buf, binaryVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(entries)))
+	for _, entity := range entries {
+		payload, err := s.codec.Encode(entity.item)
+		if err != nil {
+			return nil, fmt.Errorf("schedule: encoding %s: %w", entity.id, err)
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(entity.id)))
+		buf = append(buf, entity.id...)
+		buf = binary.AppendVarint(buf, entity.due.UnixNano())
+		buf = binary.AppendUvarint(buf, uint64(len(payload)))
+		buf = append(buf, payload...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces everything scheduled with the items encoded in
+// data by MarshalBinary. Nothing is changed if data can't be decoded. Each
+// item is added as AddReminder would add it, growing the horizon under
+// WithMaxBlocks; one the scheduler refuses is skipped and the first such
+// error is returned once the rest have been added.
+func (s *Scheduler[T]) UnmarshalBinary(data []byte) error {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	if s.codec == nil {
+		return ErrNoCodec
+	}
+
+	decoded, err := decodeBinary(s.codec, data)
+	if err != nil {
+		return err
+	}
+
+	old := s.entries()
+	for _, bucket := range s.stores() {
+		bucket.elements = make([]*entry[T], 0)
+	}
+	for _, entity := range old {
+		s.cancel(entity)
+	}
+	var first error
+	for _, item := range decoded {
+		if _, err := s.tryAdd(newEntry(item)); err != nil && first == nil {
+			first = err
+		}
+	}
+	s.signalIfEmpty()
+
+	return first
+}
+
+func decodeBinary[T Schedulable](codec Codec[T], data []byte) ([]T, error) {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return nil, ErrCorrupt
+	}
+	data = data[1:]
+
+	// next returns the next length-prefixed field
+	next := func() ([]byte, bool) {
+		n, read := binary.Uvarint(data)
+		if read <= 0 || uint64(len(data)-read) < n {
+			return nil, false
+		}
+		field := data[read : read+int(n)]
+		data = data[read+int(n):]
+		return field, true
+	}
+
+	count, read := binary.Uvarint(data)
+	if read <= 0 {
+		return nil, ErrCorrupt
+	}
+	data = data[read:]
+
+	// Every item takes at least three bytes, so don't trust a count that
+	// claims more
+	if count > uint64(len(data)/3) {
+		return nil, ErrCorrupt
+	}
+
+	decoded := make([]T, 0, count)
+	for i := uint64(0); i < count; i++ {
+		id, ok := next()
+		if !ok {
+			return nil, ErrCorrupt
+		}
+		nanos, read := binary.Varint(data)
+		if read <= 0 {
+			return nil, ErrCorrupt
+		}
+		data = data[read:]
+		payload, ok := next()
+		if !ok {
+			return nil, ErrCorrupt
+		}
+
+		item, err := codec.Decode(string(id), time.Unix(0, nanos), payload)
+		if err != nil {
+			return nil, fmt.Errorf("schedule: decoding %s: %w", id, err)
+		}
+		decoded = append(decoded, item)
+	}
+
+	if len(data) != 0 {
+		return nil, ErrCorrupt
+	}
+	return decoded, nil
+}
@@ -0,0 +1,152 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type testItemCodec struct{}
+
+func (testItemCodec) Encode(item testItem) ([]byte, error) {
+	return nil, nil
+}
+
+func (testItemCodec) Decode(id string, due time.Time, payload []byte) (testItem, error) {
+	return testItem{id: id, due: due}, nil
+}
+
+func newBinaryScheduler(clock Clock, n int) *Scheduler[testItem] {
+	start := clock.Now()
+	s := NewScheduler[testItem](context.Background(), time.Minute, 60,
+		WithClock[testItem](clock),
+		WithCodec[testItem](testItemCodec{}),
+	)
+	for i := 0; i < n; i++ {
+		s.AddReminder(testItem{id: fmt.Sprint(i), due: start.Add(time.Duration(i) * time.Millisecond)})
+	}
+	return s
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	n := 1000000
+	if testing.Short() {
+		n = 10000
+	}
+
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := newBinaryScheduler(clock, n)
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() = %v", err)
+	}
+
+	restored := newBinaryScheduler(clock, 3)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() = %v", err)
+	}
+	if restored.Len() != n {
+		t.Fatalf("restored %d items, want %d", restored.Len(), n)
+	}
+
+	clock.Advance(time.Hour)
+	due := restored.Due()
+	if len(due) != n {
+		t.Fatalf("%d restored items due, want %d", len(due), n)
+	}
+	for i, item := range due {
+		if want := start.Add(time.Duration(i) * time.Millisecond); item.id != fmt.Sprint(i) || !item.due.Equal(want) {
+			t.Fatalf("item %d restored as %s due %s, want %d due %s", i, item.id, item.due, i, want)
+		}
+	}
+}
+
+func TestUnmarshalBinaryCorrupt(t *testing.T) {
+	clock := NewFakeClock(time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC))
+	data, _ := newBinaryScheduler(clock, 10).MarshalBinary()
+
+	s := newBinaryScheduler(clock, 3)
+	for _, bad := range [][]byte{nil, {9}, data[:len(data)-1], append(data, 0)} {
+		if err := s.UnmarshalBinary(bad); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("UnmarshalBinary(%d bytes) = %v, want ErrCorrupt", len(bad), err)
+		}
+	}
+	if s.Len() != 3 {
+		t.Errorf("Len() = %d after failed restores, want 3", s.Len())
+	}
+
+	if _, err := NewScheduler[testItem](context.Background(), time.Second, 4).MarshalBinary(); err != ErrNoCodec {
+		t.Errorf("MarshalBinary() without a codec = %v, want ErrNoCodec", err)
+	}
+}
+
+func BenchmarkMarshalBinary(b *testing.B) {
+	s := newBinaryScheduler(NewFakeClock(time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)), 100000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := s.MarshalBinary(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkMarshalJSON encodes the same id and due time pairs as JSON, as a
+// baseline for MarshalBinary.
+func BenchmarkMarshalJSON(b *testing.B) {
+	s := newBinaryScheduler(NewFakeClock(time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)), 100000)
+	b.ResetTimer()
+
+	type pair struct {
+		Id  string    `json:"id"`
+		Due time.Time `json:"due"`
+	}
+	for i := 0; i < b.N; i++ {
+		s.mutex.Lock()
+		entries := s.entries()
+		s.mutex.Unlock()
+
+		sortByDue(entries)
+		pairs := make([]pair, 0, len(entries))
+		for _, entity := range entries {
+			pairs = append(pairs, pair{entity.id, entity.due})
+		}
+		if _, err := json.Marshal(pairs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestUnmarshalBinaryAdmission(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	source := newBinaryScheduler(clock, 0)
+	source.AddReminder(testItem{id: "a", due: start.Add(30 * time.Second)})
+	source.AddReminder(testItem{id: "a", due: start.Add(90 * time.Second)})
+	source.AddReminder(testItem{id: "far", due: start.Add(5*time.Minute + 30*time.Second)})
+	data, err := source.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() = %v", err)
+	}
+
+	s := NewScheduler[testItem](context.Background(), time.Minute, 2,
+		WithClock[testItem](clock),
+		WithCodec[testItem](testItemCodec{}),
+		WithDedupe[testItem](DedupeReject),
+		WithMaxBlocks[testItem](8),
+	)
+	if err := s.UnmarshalBinary(data); !errors.Is(err, ErrDuplicateId) {
+		t.Errorf("UnmarshalBinary() = %v, want %v for the second a", err, ErrDuplicateId)
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d, want the duplicate of a refused", s.Len())
+	}
+	if windows := s.Windows(); len(windows) != 6 {
+		t.Errorf("got %d buckets, want the horizon grown to reach far", len(windows))
+	}
+}
@@ -0,0 +1,70 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+// Callback is a ready-made Schedulable that carries the function to run when
+// it is due.
+type Callback struct {
+	ID  string
+	Due time.Time
+	Fn  func()
+}
+
+func (c Callback) DueTime() time.Time {
+	return c.Due
+}
+
+func (c Callback) Id() string {
+	return c.ID
+}
+
+// CallbackScheduler is a Scheduler of Callbacks for the simple "do this at
+// that time" case, where each item runs its own function instead of being
+// dispatched to a shared handler.
+type CallbackScheduler struct {
+	*Scheduler[Callback]
+}
+
+// NewCallbackScheduler returns a CallbackScheduler. Any WithOnDue or
+// WithRouter in opts is ignored, since each callback is its own handler.
+func NewCallbackScheduler(ctx context.Context, blockSize time.Duration, numBlocks int, opts ...Option[Callback]) *CallbackScheduler {
+	opts = append(opts, func(s *Scheduler[Callback]) {
+		s.router = nil
+		s.onDue = func(cb Callback) error {
+			invoke(s.logger, cb)
+			return nil
+		}
+	})
+	return &CallbackScheduler{
+		Scheduler: NewScheduler[Callback](ctx, blockSize, numBlocks, opts...),
+	}
+}
+
+// Schedule adds a callback with the given id that runs fn once it is due.
+func (c *CallbackScheduler) Schedule(id string, due time.Time, fn func()) error {
+	return c.AddReminder(Callback{ID: id, Due: due, Fn: fn})
+}
+
+// Start runs the background loop, as Scheduler.Start does, calling each
+// callback's function on the loop's goroutine as it comes due. A function
+// that panics is logged at LevelWarn and doesn't stop the loop or run again.
+// Stop ends the loop.
+func (c *CallbackScheduler) Start() {
+	c.Scheduler.Start()
+}
+
+// invoke calls cb's function, recovering from any panic.
+func invoke(logger Logger, cb Callback) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Log(LevelWarn, "callback panicked", "id", cb.ID, "panic", r)
+		}
+	}()
+
+	if cb.Fn != nil {
+		cb.Fn()
+	}
+}
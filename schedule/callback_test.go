@@ -0,0 +1,38 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCallbackScheduler(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewCallbackScheduler(context.Background(), time.Second, 4, WithClock[Callback](clock))
+
+	ran := make(chan string, 3)
+	s.Schedule("boom", start.Add(500*time.Millisecond), func() { panic("boom") })
+	s.Schedule("first", start.Add(600*time.Millisecond), func() { ran <- "first" })
+	s.Schedule("second", start.Add(1500*time.Millisecond), func() { ran <- "second" })
+
+	s.Start()
+	defer s.Stop()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	if got := <-ran; got != "first" {
+		t.Errorf("ran %s, want first", got)
+	}
+
+	// The panic didn't take the loop down
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	if got := <-ran; got != "second" {
+		t.Errorf("ran %s, want second", got)
+	}
+
+	if n := s.Len(); n != 0 {
+		t.Errorf("Len() = %d, want 0", n)
+	}
+}
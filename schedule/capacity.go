@@ -0,0 +1,29 @@
+package schedule
+
+// WithCapacity bounds the scheduler to n items. Once it holds n, adding more
+// fails with ErrCapacity until something fires or is removed. Recurring items
+// being scheduled again and retries of failed deliveries don't count as new
+// items and are never refused. The default is unbounded.
+func WithCapacity[T Schedulable](n int) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.capacity = n
+	}
+}
+
+// Utilization returns the fraction of the capacity set by WithCapacity that
+// is in use, or 0 if the scheduler is unbounded.
+func (s *Scheduler[T]) Utilization() float64 {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	if s.capacity <= 0 {
+		return 0
+	}
+	return float64(s.size()) / float64(s.capacity)
+}
+
+// full reports whether the scheduler is at its capacity.
+func (s *Scheduler[T]) full() bool {
+	return s.capacity > 0 && s.size() >= s.capacity
+}
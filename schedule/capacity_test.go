@@ -0,0 +1,45 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCapacity(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithCapacity[testItem](4),
+	)
+
+	for i, id := range []string{"a", "b", "c"} {
+		if err := s.AddReminder(testItem{id: id, due: start.Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatalf("AddReminder(%s) = %v", id, err)
+		}
+	}
+	if u := s.Utilization(); u != 0.75 {
+		t.Errorf("Utilization() = %v, want 0.75", u)
+	}
+
+	s.AddReminder(testItem{id: "d", due: start.Add(time.Second)})
+	if err := s.AddReminder(testItem{id: "e", due: start.Add(time.Second)}); err != ErrCapacity {
+		t.Errorf("AddReminder at capacity = %v, want ErrCapacity", err)
+	}
+	if u := s.Utilization(); u != 1 {
+		t.Errorf("Utilization() = %v, want 1", u)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	s.Due()
+	if err := s.AddReminder(testItem{id: "e", due: start.Add(time.Second)}); err != nil {
+		t.Errorf("AddReminder after an item fired = %v, want nil", err)
+	}
+
+	unbounded := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+	unbounded.AddReminder(testItem{id: "a", due: start})
+	if u := unbounded.Utilization(); u != 0 {
+		t.Errorf("unbounded Utilization() = %v, want 0", u)
+	}
+}
@@ -0,0 +1,169 @@
+package schedule
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time used by a Scheduler, including for the timers
+// that drive its background loop.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the subset of time.Timer the scheduler needs.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// FakeClock is a Clock that only moves when told to, for deterministic tests
+// and simulations. Its timers fire when Advance or Set moves it past their
+// deadline.
+type FakeClock struct {
+	now    time.Time
+	timers []*fakeTimer
+	lock   *sync.Mutex
+	cond   *sync.Cond
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	lock := &sync.Mutex{}
+	return &FakeClock{
+		now:  now,
+		lock: lock,
+		cond: sync.NewCond(lock),
+	}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+	c.fire()
+}
+
+func (c *FakeClock) Set(now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = now
+	c.fire()
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{
+		clock: c,
+		ch:    make(chan time.Time, 1),
+	}
+	t.Reset(d)
+	return t
+}
+
+// BlockUntil waits until at least n timers are waiting on the clock, so a
+// test can be sure a goroutine is asleep before advancing past its deadline.
+func (c *FakeClock) BlockUntil(n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// fire triggers every timer whose deadline has passed. It must be called with
+// the lock held.
+func (c *FakeClock) fire() {
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.deadline.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		select {
+		case t.ch <- c.now:
+		default:
+		}
+	}
+	c.timers = pending
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.lock.Lock()
+	defer t.clock.lock.Unlock()
+	return t.remove()
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	active := t.remove()
+	t.deadline = c.now.Add(d)
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	c.fire()
+	return active
+}
+
+// remove takes the timer off the clock, reporting whether it was waiting. It
+// must be called with the clock's lock held.
+func (t *fakeTimer) remove() bool {
+	for i, other := range t.clock.timers {
+		if other == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// advancer is implemented by clocks, like FakeClock, that can be moved forward
+// by hand.
+type advancer interface {
+	Advance(d time.Duration)
+}
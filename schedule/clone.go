@@ -0,0 +1,125 @@
+package schedule
+
+import (
+	"sync"
+	"time"
+)
+
+// Clone returns an independent copy of the scheduler with the same
+// configuration and clock and a copy of every bucket and scheduled item, so
+// changes can be tried out on the clone without affecting s. The clone
+// starts with its own metrics at the same values, no event stream and no
+// dispatch loop running.
+func (s *Scheduler[T]) Clone() *Scheduler[T] {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	return s.clone()
+}
+
+// clone does the work of Clone. It must be called with the lock held.
+func (s *Scheduler[T]) clone() *Scheduler[T] {
+	clone := &Scheduler[T]{
+		ctx:             s.ctx,
+		blockSize:       s.blockSize,
+		numBlocks:       s.numBlocks,
+		mutex:           &sync.Mutex{},
+		clock:           s.clock,
+		onExpire:        s.onExpire,
+		onBucketActive:  s.onBucketActive,
+		onTick:          s.onTick,
+		deferSpread:     s.deferSpread,
+		order:           s.order,
+		dedupe:          s.dedupe,
+		seq:             s.seq,
+		lockOrder:       schedulerCount.Add(1),
+		logger:          s.logger,
+		name:            s.name,
+		dedupeKey:       s.dedupeKey,
+		onDue:           s.onDue,
+		retryBackoff:    s.retryBackoff,
+		tickInterval:    s.tickInterval,
+		reserved:        s.reserved,
+		capacity:        s.capacity,
+		release:         s.release,
+		regression:      s.regression,
+		codec:           s.codec,
+		ackTimeout:      s.ackTimeout,
+		maxTailDepth:    s.maxTailDepth,
+		flushAlignment:  s.flushAlignment,
+		maxFuture:       s.maxFuture,
+		heartbeatEvery:  s.heartbeatEvery,
+		maxConcurrent:   s.maxConcurrent,
+		precise:         s.precise,
+		dueGrid:         s.dueGrid,
+		maxBlocks:       s.maxBlocks,
+		growthExhausted: s.growthExhausted,
+		lastNow:         s.lastNow,
+		lastUpdate:      s.lastUpdate,
+		deadLetter:      s.deadLetter,
+		router:          s.router,
+		paused:          s.paused,
+		muted:           s.muted,
+		maxLateness:     s.maxLateness,
+		onDrop:          s.onDrop,
+
+		spreadRecurring: s.spreadRecurring,
+		catchUp:         s.catchUp,
+		loader:          s.loader,
+		loadedUntil:     s.loadedUntil,
+	}
+
+	clone.buckets = make([]*TimespanBucket[*entry[T]], 0, len(s.buckets))
+	for _, bucket := range s.buckets {
+		clone.buckets = append(clone.buckets, cloneBucket(bucket))
+	}
+	if s.overflow != nil {
+		clone.overflow = cloneBucket(s.overflow)
+	}
+
+	if s.handlers != nil {
+		clone.handlers = make(map[string]func(T), len(s.handlers))
+		for category, fn := range s.handlers {
+			clone.handlers[category] = fn
+		}
+	}
+
+	if s.wake != nil {
+		clone.wake = make(chan struct{}, 1)
+	}
+	if s.inflight != nil {
+		clone.inflight = make(map[uint64]time.Time, len(s.inflight))
+		for seq, deadline := range s.inflight {
+			clone.inflight[seq] = deadline
+		}
+	}
+
+	clone.spans = append([]span[T](nil), s.spans...)
+
+	if s.groups != nil {
+		clone.groups = make(map[string]int, len(s.groups))
+		for group, n := range s.groups {
+			clone.groups[group] = n
+		}
+	}
+
+	clone.metrics.scheduled.Store(s.metrics.scheduled.Load())
+	clone.metrics.fired.Store(s.metrics.fired.Load())
+	clone.metrics.cancelled.Store(s.metrics.cancelled.Load())
+	clone.metrics.expired.Store(s.metrics.expired.Load())
+	clone.metrics.dropped.Store(s.metrics.dropped.Load())
+	clone.metrics.depth.Store(s.metrics.depth.Load())
+
+	return clone
+}
+
+func cloneBucket[T Schedulable](bucket *TimespanBucket[*entry[T]]) *TimespanBucket[*entry[T]] {
+	clone := NewTimespanBucket[*entry[T]](bucket.startTime, bucket.endTime)
+	clone.elements = make([]*entry[T], 0, cap(bucket.elements))
+	for _, entity := range bucket.elements {
+		copied := *entity
+		clone.elements = append(clone.elements, &copied)
+	}
+	return clone
+}
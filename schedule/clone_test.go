@@ -0,0 +1,40 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(2500 * time.Millisecond)})
+
+	clone := s.Clone()
+	if err := clone.Check(); err != nil {
+		t.Fatalf("clone Check() = %v", err)
+	}
+
+	clone.AddReminder(testItem{id: "c", due: start.Add(700 * time.Millisecond)})
+	clone.Shift(time.Second)
+	clone.Cancel("b")
+
+	if s.Len() != 2 || clone.Len() != 2 {
+		t.Errorf("Len() = %d, clone Len() = %d, want 2 and 2", s.Len(), clone.Len())
+	}
+	if next, _ := s.NextDueTime(); !next.Equal(start.Add(500 * time.Millisecond)) {
+		t.Errorf("NextDueTime() = %s after changing the clone, want +500ms", next.Sub(start))
+	}
+
+	clock.Advance(time.Second)
+	if due := s.Due(); len(due) != 1 || due[0].id != "a" {
+		t.Errorf("Due() = %v, want [a]", due)
+	}
+	if due := clone.Due(); len(due) != 0 {
+		t.Errorf("clone Due() = %v, want nothing", due)
+	}
+}
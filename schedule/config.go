@@ -0,0 +1,73 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// Config is a snapshot of a scheduler's settings, for logging and auditing.
+type Config struct {
+	Name      string
+	BlockSize time.Duration
+	NumBlocks int
+	// Clock is the type name of the clock in use.
+	Clock string
+
+	Capacity        int
+	Reserved        int
+	Overflow        bool
+	Dedupe          DedupePolicy
+	Order           Order
+	DeferSpread     time.Duration
+	MaxLateness     time.Duration
+	TickInterval    time.Duration
+	CatchUp         CatchUpMode
+	SpreadByHash    bool
+	OverdueRelease  OverdueRelease
+	Regression      ClockRegression
+	AckTimeout      time.Duration
+	MaxTailDepth    int
+	FlushAlignment  time.Duration
+	MaxFuture       time.Duration
+	Heartbeat       time.Duration
+	MaxConcurrent   int
+	PreciseTimers   bool
+	DueGrid         time.Duration
+	MaxBlocks       int
+	GrowthExhausted GrowthExhausted
+}
+
+// Config returns the scheduler's current settings.
+func (s *Scheduler[T]) Config() Config {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	return Config{
+		Name:            s.name,
+		BlockSize:       s.blockSize,
+		NumBlocks:       s.numBlocks,
+		Clock:           fmt.Sprintf("%T", s.clock),
+		Capacity:        s.capacity,
+		Reserved:        s.reserved,
+		Overflow:        s.overflow != nil,
+		Dedupe:          s.dedupe,
+		Order:           s.order,
+		DeferSpread:     s.deferSpread,
+		MaxLateness:     s.maxLateness,
+		TickInterval:    s.tickInterval,
+		CatchUp:         s.catchUp,
+		SpreadByHash:    s.spreadRecurring,
+		OverdueRelease:  s.release,
+		Regression:      s.regression,
+		AckTimeout:      s.ackTimeout,
+		MaxTailDepth:    s.maxTailDepth,
+		FlushAlignment:  s.flushAlignment,
+		MaxFuture:       s.maxFuture,
+		Heartbeat:       s.heartbeatEvery,
+		MaxConcurrent:   s.maxConcurrent,
+		PreciseTimers:   s.precise,
+		DueGrid:         s.dueGrid,
+		MaxBlocks:       s.maxBlocks,
+		GrowthExhausted: s.growthExhausted,
+	}
+}
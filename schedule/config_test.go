@@ -0,0 +1,40 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConfig(t *testing.T) {
+	clock := NewFakeClock(time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC))
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithName[testItem]("billing"),
+		WithCapacity[testItem](100),
+		WithOverflow[testItem](),
+		WithDedupe[testItem](DedupeReject),
+		WithStrictFIFO[testItem](),
+		WithOverdueRelease[testItem](RateLimited(10, time.Second)),
+	)
+
+	want := Config{
+		Name:           "billing",
+		BlockSize:      time.Second,
+		NumBlocks:      4,
+		Clock:          "*schedule.FakeClock",
+		Capacity:       100,
+		Overflow:       true,
+		Dedupe:         DedupeReject,
+		Order:          InsertionOrder,
+		OverdueRelease: RateLimited(10, time.Second),
+	}
+	if got := s.Config(); got != want {
+		t.Errorf("Config() = %+v, want %+v", got, want)
+	}
+
+	s.SetResolution(time.Minute)
+	if got := s.Config().BlockSize; got != time.Minute {
+		t.Errorf("Config().BlockSize = %s after SetResolution, want 1m", got)
+	}
+}
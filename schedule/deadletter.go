@@ -0,0 +1,57 @@
+package schedule
+
+// Reason says why an item will never be delivered.
+type Reason int
+
+const (
+	// Expired items passed their ExpiresAt before they were delivered.
+	Expired Reason = iota
+	// TooLate items were later than WithMaxLateness allows.
+	TooLate
+	// Abandoned items were due but still undelivered when the loop started
+	// by Start stopped.
+	Abandoned
+	// Unrouted items had no handler for their WithRouter category, or no
+	// handler at all under StartN.
+	Unrouted
+	// BeyondHorizon items were due further ahead than the horizon could
+	// grow, under the DeadLetter growth policy.
+	BeyondHorizon
+)
+
+func (r Reason) String() string {
+	switch r {
+	case Expired:
+		return "expired"
+	case TooLate:
+		return "too late"
+	case Abandoned:
+		return "abandoned"
+	case Unrouted:
+		return "unrouted"
+	case BeyondHorizon:
+		return "beyond horizon"
+	default:
+		return "unknown"
+	}
+}
+
+// WithDeadLetter registers fn to be called, outside the scheduler's lock,
+// with every item that will never be delivered and why, as a single place to
+// record lost work. It is called in addition to WithOnExpire and WithOnDrop.
+func WithDeadLetter[T Schedulable](fn func(item T, reason Reason)) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.deadLetter = fn
+	}
+}
+
+// lost queues entity for the dead letter handler. It must be called with the
+// mutex held.
+func (s *Scheduler[T]) lost(entity *entry[T], reason Reason) {
+	if s.deadLetter != nil {
+		deadLetter := s.deadLetter
+		s.later(func() {
+			deadLetter(entity.item, reason)
+		})
+	}
+}
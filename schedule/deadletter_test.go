@@ -0,0 +1,48 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestDeadLetter(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	var lost []string
+	s := NewScheduler[expiringItem](context.Background(), time.Second, 4,
+		WithClock[expiringItem](clock),
+		WithMaxLateness[expiringItem](time.Minute),
+		WithDeadLetter(func(item expiringItem, reason Reason) {
+			lost = append(lost, item.id+": "+reason.String())
+		}),
+	)
+
+	s.AddReminder(expiringItem{testItem{id: "expires", due: start.Add(time.Second)}, start.Add(time.Second)})
+	s.AddReminder(expiringItem{testItem{id: "late", due: start.Add(-2 * time.Minute)}, time.Time{}})
+	s.AddReminder(expiringItem{testItem{id: "abandoned", due: start.Add(2 * time.Second)}, time.Time{}})
+	s.AddReminder(expiringItem{testItem{id: "delivered", due: start.Add(3 * time.Second)}, time.Time{}})
+
+	clock.Advance(1500 * time.Millisecond)
+	s.Due()
+
+	// Nobody receives from the loop, so whatever it takes is abandoned
+	s.Start()
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	for s.Len() > 1 {
+		time.Sleep(time.Millisecond)
+	}
+	s.Stop()
+	for s.Running() {
+		time.Sleep(time.Millisecond)
+	}
+
+	sort.Strings(lost)
+	if want := "[abandoned: abandoned expires: expired late: too late]"; fmt.Sprint(lost) != want {
+		t.Errorf("dead letters = %v, want %s", lost, want)
+	}
+}
@@ -0,0 +1,343 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+// Start runs a background loop that checks for due items every blockSize (or
+// WithTickInterval) and sends them on the returned channel, or passes them to
+// the WithRouter handlers or the WithOnDue handler if there are any, in which
+// case nothing is sent on the channel. The loop stops and the channel is
+// closed once Stop is called or the scheduler's context is done; an item that
+// was due but not yet received at that point is dropped and passed to
+// WithDeadLetter. Calling Start again while the loop is running returns the
+// same channel, which is nil if the loop was started by StartN.
+func (s *Scheduler[T]) Start() <-chan T {
+	out, _ := s.start(make(chan T), nil)
+	return out
+}
+
+// start launches the loop, which hands due items to work if it is set and
+// otherwise to the handlers or out, and returns out. If a loop is already
+// running it does nothing and returns that loop's out and false instead.
+func (s *Scheduler[T]) start(out chan T, work chan<- *entry[T]) (<-chan T, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stopLoop != nil {
+		return s.out, false
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+
+	var heartbeat chan time.Time
+	if s.heartbeatEvery > 0 {
+		heartbeat = make(chan time.Time, 1)
+	}
+
+	s.stopLoop = cancel
+	s.out = out
+	s.heartbeat = heartbeat
+
+	// A loop that was stopped may still be finishing, so count loops rather
+	// than letting it mark this one as stopped when it exits
+	s.running.Add(1)
+	go s.run(ctx, out, work, heartbeat)
+	return out, true
+}
+
+// Stop asks the loop started by Start to exit. The loop finishes
+// asynchronously, so Running may still report true for a short while after
+// Stop returns.
+func (s *Scheduler[T]) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stopLoop != nil {
+		s.stopLoop()
+		s.stopLoop = nil
+	}
+}
+
+// Running reports whether the loop started by Start is still running.
+func (s *Scheduler[T]) Running() bool {
+	return s.running.Load() > 0
+}
+
+// WithOnDue makes the loop started by Start call fn with each due item. If fn
+// returns an error the item is scheduled again after the WithRetryBackoff
+// delay.
+func WithOnDue[T Schedulable](fn func(T) error) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.onDue = fn
+	}
+}
+
+// WithRetryBackoff sets how long to wait before retrying an item whose OnDue
+// handler failed, given how many times it has now failed. The default is to
+// retry after one blockSize.
+func WithRetryBackoff[T Schedulable](backoff func(attempt int) time.Duration) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.retryBackoff = backoff
+	}
+}
+
+// WithTickInterval makes the loop started by Start check for due items every
+// d instead of every blockSize, so items are delivered at most d after they
+// come due without narrowing the buckets. Shorter intervals mean lower
+// latency at the cost of waking up, and taking the lock, more often. The
+// interval is timed with the scheduler's clock.
+func WithTickInterval[T Schedulable](d time.Duration) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.tickInterval = d
+	}
+}
+
+// WithFlushAlignment makes the loop started by Start deliver only on
+// boundaries aligned to d, such as :00, :05, :10 for five minutes, instead of
+// checking every blockSize. Items that come due between boundaries stay
+// scheduled until the next one, when everything due is delivered together.
+// Boundaries are computed from the scheduler's clock relative to the zero
+// time, so they fall on the hour in UTC for any d that divides an hour. It
+// takes precedence over WithTickInterval.
+func WithFlushAlignment[T Schedulable](d time.Duration) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.flushAlignment = d
+	}
+}
+
+// WithHeartbeat makes the loop started by Start send the time on the Heartbeat
+// channel every d, whether or not anything is due, so a consumer can tell an
+// idle scheduler from one whose loop has died or stalled.
+func WithHeartbeat[T Schedulable](d time.Duration) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.heartbeatEvery = d
+	}
+}
+
+// Heartbeat returns the channel the loop started by the latest Start sends
+// heartbeats on, timed by the scheduler's clock. It holds at most one
+// heartbeat, so a consumer that falls behind misses some rather than blocking
+// the loop, and is closed when the loop stops. It is nil without
+// WithHeartbeat or before Start.
+func (s *Scheduler[T]) Heartbeat() <-chan time.Time {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	return s.heartbeat
+}
+
+// pollInterval returns how long the loop started by Start waits until its
+// next check.
+func (s *Scheduler[T]) pollInterval() time.Duration {
+	if d := s.flushAlignment; d > 0 {
+		now := s.clock.Now()
+		return now.Truncate(d).Add(d).Sub(now)
+	}
+	poll := s.tickInterval
+	if poll <= 0 {
+		poll = s.resolution()
+	}
+	if s.precise && s.flushAlignment <= 0 {
+		return s.untilNextDue(poll)
+	}
+	return poll
+}
+
+func (s *Scheduler[T]) run(ctx context.Context, out chan<- T, work chan<- *entry[T], heartbeat chan<- time.Time) {
+	if out != nil {
+		defer close(out)
+	}
+	if work != nil {
+		defer close(work)
+	}
+	defer s.running.Add(-1)
+
+	timer := s.clock.NewTimer(s.pollInterval())
+	defer timer.Stop()
+
+	var beat Timer
+	var beats <-chan time.Time
+	if heartbeat != nil {
+		defer close(heartbeat)
+		beat = s.clock.NewTimer(s.heartbeatEvery)
+		defer beat.Stop()
+		beats = beat.C()
+	}
+
+	// With flush alignment nothing is delivered until the first boundary
+	waiting := s.flushAlignment > 0
+
+	// Precise timers are re-armed after each delivery pass, for whatever is
+	// soonest by then, rather than when they fire
+	var wake <-chan struct{}
+	if s.precise && s.flushAlignment <= 0 {
+		wake = s.wake
+	}
+
+	for {
+		var due []*entry[T]
+		delivering := !waiting
+		if delivering {
+			due = s.dueEntries()
+		}
+		waiting = false
+
+		for i, entity := range due {
+			if work != nil {
+				select {
+				case work <- entity:
+				case <-ctx.Done():
+					s.abandon(due[i:])
+					return
+				}
+				continue
+			}
+			if s.handle(entity) {
+				continue
+			}
+
+			select {
+			case out <- entity.item:
+			case <-ctx.Done():
+				s.abandon(due[i:])
+				return
+			}
+		}
+
+		if wake != nil && delivering {
+			timer.Reset(s.pollInterval())
+		}
+
+		select {
+		case <-timer.C():
+			if wake == nil {
+				timer.Reset(s.pollInterval())
+			}
+		case <-wake:
+			// Something sooner than the timer was added
+		case now := <-beats:
+			// A heartbeat on its own delivers nothing
+			select {
+			case heartbeat <- now:
+			default:
+			}
+			beat.Reset(s.heartbeatEvery)
+			waiting = true
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handle passes entity to the WithRouter or WithOnDue handler, reporting
+// false if there is neither.
+func (s *Scheduler[T]) handle(entity *entry[T]) bool {
+	if s.router != nil {
+		s.route(entity)
+		return true
+	}
+	if s.onDue != nil {
+		if err := s.onDue(entity.item); err != nil {
+			s.retry(entity, err)
+		}
+		return true
+	}
+	return false
+}
+
+func (s *Scheduler[T]) dueEntries() []*entry[T] {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	limit := s.releaseLimit()
+	due := s.takeDue(limit)
+	if limit >= 0 {
+		s.released += len(due)
+	}
+	return due
+}
+
+// OverdueRelease controls how fast the loop started by Start hands out a
+// backlog of overdue items, such as the ones that built up during an outage.
+type OverdueRelease struct {
+	n        int
+	interval time.Duration
+}
+
+// AllAtOnce releases every overdue item as soon as the loop sees it.
+var AllAtOnce = OverdueRelease{}
+
+// RateLimited releases at most n items every interval while there are
+// overdue items. The loop checks every blockSize, or WithTickInterval, so
+// interval should be a multiple of that.
+func RateLimited(n int, interval time.Duration) OverdueRelease {
+	return OverdueRelease{n: n, interval: interval}
+}
+
+// WithOverdueRelease sets how the loop started by Start releases overdue
+// items. The default is AllAtOnce.
+func WithOverdueRelease[T Schedulable](release OverdueRelease) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.release = release
+	}
+}
+
+// releaseLimit returns how many items the loop may hand out now, or -1 for no
+// limit.
+func (s *Scheduler[T]) releaseLimit() int {
+	if s.release.n <= 0 || s.overdueCount() == 0 {
+		return -1
+	}
+
+	now := s.now()
+	if now.Sub(s.releaseStart) >= s.release.interval {
+		s.releaseStart = now
+		s.released = 0
+	}
+
+	if s.released >= s.release.n {
+		return 0
+	}
+	return s.release.n - s.released
+}
+
+// abandon hands entries that were taken but won't be delivered to the dead
+// letter handler.
+func (s *Scheduler[T]) abandon(entries []*entry[T]) {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	s.logger.Log(LevelWarn, "abandoned due items", "count", len(entries))
+	for _, entity := range entries {
+		s.lost(entity, Abandoned)
+	}
+}
+
+// retry schedules a copy of an entry whose handler failed. The copy doesn't
+// recur, since a recurring entry has already been scheduled for its next
+// occurrence.
+func (s *Scheduler[T]) retry(entity *entry[T], err error) {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	retry := *entity
+	retry.attempt++
+	retry.interval = 0
+	s.seq++
+	retry.seq = s.seq
+
+	backoff := s.blockSize
+	if s.retryBackoff != nil {
+		backoff = s.retryBackoff(retry.attempt)
+	}
+	retry.due = s.now().Add(backoff)
+
+	s.logger.Log(LevelDebug, "retrying failed item", "id", retry.id, "attempt", retry.attempt, "due", retry.due, "error", err)
+	s.join(&retry)
+	s.reschedule(&retry)
+	s.nudge(retry.due)
+}
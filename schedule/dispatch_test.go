@@ -0,0 +1,346 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStart(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewScheduler[testItem](ctx, time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(1500 * time.Millisecond)})
+	out := s.Start()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+
+	select {
+	case item := <-out:
+		if item.id != "a" {
+			t.Errorf("got %s, want a", item.id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the due item")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Error("unexpected item after cancelling")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed after cancelling the context")
+	}
+}
+
+func TestTickInterval(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithTickInterval[testItem](100*time.Millisecond),
+	)
+
+	s.AddReminder(testItem{id: "a", due: start.Add(250 * time.Millisecond)})
+	out := s.Start()
+	defer s.Stop()
+
+	for i := 0; i < 3; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(100 * time.Millisecond)
+	}
+
+	select {
+	case item := <-out:
+		if now := clock.Now(); now.Sub(item.due) > 100*time.Millisecond {
+			t.Errorf("delivered %s after it was due, want at most one tick", now.Sub(item.due))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the due item well before the bucket ended")
+	}
+}
+
+func TestFlushAlignment(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 2, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Minute, 10,
+		WithClock[testItem](clock),
+		WithFlushAlignment[testItem](5*time.Minute),
+	)
+
+	s.AddReminder(testItem{id: "a", due: start.Add(time.Minute)})
+	s.AddReminder(testItem{id: "b", due: start.Add(2 * time.Minute)})
+	s.AddReminder(testItem{id: "c", due: start.Add(4 * time.Minute)})
+	out := s.Start()
+	defer s.Stop()
+
+	// a is due mid-window and waits for the boundary at 11:05
+	clock.BlockUntil(1)
+	clock.Advance(90 * time.Second)
+	select {
+	case item := <-out:
+		t.Fatalf("delivered %s at %s, before the boundary", item.id, clock.Now().Format("15:04:05"))
+	case <-time.After(50 * time.Millisecond):
+	}
+	if n := s.Len(); n != 3 {
+		t.Errorf("Len() = %d mid-window, want 3", n)
+	}
+
+	clock.Advance(90 * time.Second)
+	for _, want := range []string{"a", "b"} {
+		select {
+		case item := <-out:
+			if item.id != want {
+				t.Errorf("delivered %s, want %s", item.id, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s at the boundary", want)
+		}
+	}
+
+	// c, due at 11:06, waits for 11:10
+	clock.BlockUntil(1)
+	clock.Advance(4 * time.Minute)
+	select {
+	case item := <-out:
+		t.Fatalf("delivered %s at %s, before the boundary", item.id, clock.Now().Format("15:04:05"))
+	case <-time.After(50 * time.Millisecond):
+	}
+	clock.Advance(time.Minute)
+	select {
+	case item := <-out:
+		if item.id != "c" {
+			t.Errorf("delivered %s, want c", item.id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for c at the boundary")
+	}
+}
+
+func TestOverdueRateLimited(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithOverdueRelease[testItem](RateLimited(10, time.Second)),
+	)
+
+	for i := 0; i < 25; i++ {
+		s.AddReminder(testItem{id: fmt.Sprintf("%02d", i), due: start.Add(-time.Minute)})
+	}
+	out := s.Start()
+	defer s.Stop()
+
+	for second, want := range []int{10, 10, 5} {
+		if second > 0 {
+			clock.BlockUntil(1)
+			clock.Advance(time.Second)
+		}
+
+		got := 0
+	receive:
+		for {
+			select {
+			case <-out:
+				got++
+			case <-time.After(50 * time.Millisecond):
+				break receive
+			}
+		}
+		if got != want {
+			t.Errorf("second %d: released %d items, want %d", second, got, want)
+		}
+	}
+}
+
+func TestRunning(t *testing.T) {
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](NewFakeClock(time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC))))
+
+	if s.Running() {
+		t.Fatal("Running() = true before Start")
+	}
+
+	out := s.Start()
+	if !s.Running() {
+		t.Fatal("Running() = false after Start")
+	}
+
+	s.Stop()
+	for range out {
+	}
+	if s.Running() {
+		t.Error("Running() = true after the loop exited")
+	}
+}
+
+func TestStartTwice(t *testing.T) {
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](NewFakeClock(time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC))))
+
+	out := s.Start()
+	if again := s.Start(); again != out {
+		t.Fatal("Start() while running returned a different channel")
+	}
+	s.StartN(2)
+
+	// One Stop ends the only loop there is
+	s.Stop()
+	for range out {
+	}
+	if s.Running() {
+		t.Fatal("Running() = true after the loop exited")
+	}
+
+	// A new loop isn't reported as stopped when an old one exits
+	first := s.Start()
+	s.Stop()
+	second := s.Start()
+	for range first {
+	}
+	if !s.Running() {
+		t.Error("Running() = false with the second loop still running")
+	}
+	s.Stop()
+	for range second {
+	}
+}
+
+func TestOnDueRetry(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handled := make(chan time.Time, 10)
+	calls := 0
+	var attempts []int
+	s := NewScheduler[testItem](ctx, time.Second, 10,
+		WithClock[testItem](clock),
+		WithOnDue(func(item testItem) error {
+			handled <- clock.Now()
+			if calls++; calls < 3 {
+				return errors.New("downstream unavailable")
+			}
+			return nil
+		}),
+		WithRetryBackoff[testItem](func(attempt int) time.Duration {
+			attempts = append(attempts, attempt)
+			return time.Duration(attempt) * 2 * time.Second
+		}),
+	)
+
+	s.AddReminder(testItem{id: "a", due: start})
+	s.Start()
+
+	var times []time.Duration
+	for len(times) < 3 {
+		select {
+		case at := <-handled:
+			times = append(times, at.Sub(start))
+		case <-time.After(time.Second):
+			t.Fatalf("timed out after handling %v", times)
+		}
+		clock.BlockUntil(1)
+		for i := 0; i < 4 && len(handled) == 0; i++ {
+			clock.Advance(time.Second)
+			clock.BlockUntil(1)
+		}
+	}
+
+	if fmt.Sprint(times) != "[0s 2s 6s]" || fmt.Sprint(attempts) != "[1 2]" {
+		t.Errorf("handled at %v with attempts %v, want [0s 2s 6s] and [1 2]", times, attempts)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Minute, 4,
+		WithClock[testItem](clock),
+		WithHeartbeat[testItem](time.Second),
+	)
+
+	if hb := s.Heartbeat(); hb != nil {
+		t.Fatal("Heartbeat() before Start is not nil")
+	}
+	s.Start()
+	hb := s.Heartbeat()
+
+	// Nothing is scheduled, yet the loop still beats
+	for i := 1; i <= 2; i++ {
+		clock.BlockUntil(2)
+		clock.Advance(time.Second)
+		select {
+		case at := <-hb:
+			if want := start.Add(time.Duration(i) * time.Second); !at.Equal(want) {
+				t.Errorf("heartbeat at %s, want %s", at, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for heartbeat %d", i)
+		}
+	}
+
+	s.Stop()
+	select {
+	case _, ok := <-hb:
+		if ok {
+			t.Error("received a heartbeat after Stop, want the channel closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("heartbeat channel was not closed after Stop")
+	}
+}
+
+func TestPreciseTimers(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Minute, 4,
+		WithClock[testItem](clock),
+		WithPreciseTimers[testItem](),
+	)
+
+	s.AddReminder(testItem{id: "a", due: start.Add(30 * time.Second)})
+	out := s.Start()
+	defer s.Stop()
+
+	receive := func(want string) {
+		t.Helper()
+		select {
+		case item := <-out:
+			if item.id != want {
+				t.Errorf("delivered %s, want %s", item.id, want)
+			}
+			if !clock.Now().Equal(item.due) {
+				t.Errorf("delivered %s at %s, want exactly when due at %s", item.id, clock.Now().Format("15:04:05"), item.due.Format("15:04:05"))
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+
+	// Delivered on the dot rather than at the end of the minute-wide bucket
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Second)
+	receive("a")
+
+	// Something sooner than the armed timer re-arms it
+	clock.BlockUntil(1)
+	s.AddReminder(testItem{id: "c", due: start.Add(50 * time.Second)})
+	clock.BlockUntil(1)
+	s.AddReminder(testItem{id: "b", due: start.Add(40 * time.Second)})
+	for clock.Now().Before(start.Add(40 * time.Second)) {
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+	}
+	receive("b")
+}
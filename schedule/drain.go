@@ -0,0 +1,182 @@
+package schedule
+
+import (
+	"container/heap"
+	"time"
+)
+
+// DrainDue removes and returns everything that is due, from whichever buckets
+// it is in, in due-time order (or the WithOrder order).
+func (s *Scheduler[T]) DrainDue() []T {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	now := s.now()
+	return items(s.drain(now, func(e *entry[T]) bool {
+		return !e.due.After(now)
+	}))
+}
+
+// DueBefore removes and returns every item due before t, which may be in the
+// future, in due-time order (or the WithOrder order).
+func (s *Scheduler[T]) DueBefore(t time.Time) []T {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	return items(s.drain(t, func(e *entry[T]) bool {
+		return e.due.Before(t)
+	}))
+}
+
+// TakeOverdue removes and returns only the overdue items, those due before
+// the head bucket's window began, in due-time order (or the WithOrder order),
+// leaving items that are due now or later in place.
+func (s *Scheduler[T]) TakeOverdue() []T {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	if !s.delivering() {
+		return make([]T, 0)
+	}
+
+	head := s.buckets[0]
+	overdue := head.removeWhere(func(e *entry[T]) bool {
+		return e.due.Before(head.startTime) && !e.held
+	})
+
+	now := s.now()
+	taken := make([]*entry[T], 0, len(overdue))
+	for _, entity := range overdue {
+		if entity.expired(now) {
+			s.expire(entity)
+			continue
+		}
+		taken = append(taken, entity)
+	}
+
+	sortForDelivery(s.order, taken)
+	s.fire(taken)
+	s.signalIfEmpty()
+
+	return items(taken)
+}
+
+// DrainAll removes and returns every pending item, whether or not it is due,
+// in due-time order, e.g. to flush everything downstream on shutdown. The
+// scheduler is left empty but still usable. Items are removed rather than
+// fired, like Swap, so recurring items don't come back.
+func (s *Scheduler[T]) DrainAll() []T {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	drained := s.entries()
+	for _, bucket := range s.stores() {
+		bucket.elements = make([]*entry[T], 0)
+	}
+
+	sortByDue(drained)
+	for _, entity := range drained {
+		s.cancel(entity)
+	}
+	s.signalIfEmpty()
+
+	return items(drained)
+}
+
+// drain removes the entries matching pred from every bucket that could hold
+// something due by cutoff, merges them into a single due-ordered run and
+// fires them.
+func (s *Scheduler[T]) drain(cutoff time.Time, pred func(*entry[T]) bool) []*entry[T] {
+	if !s.delivering() {
+		return make([]*entry[T], 0)
+	}
+
+	now := s.now()
+
+	runs := make([][]*entry[T], 0)
+	for idx, bucket := range s.stores() {
+		if idx > 0 && bucket.startTime.After(cutoff) {
+			break
+		}
+
+		run := make([]*entry[T], 0)
+		for _, entity := range bucket.removeWhere(func(e *entry[T]) bool { return !e.held && pred(e) }) {
+			if entity.expired(now) {
+				s.expire(entity)
+				continue
+			}
+			run = append(run, entity)
+		}
+		sortByDue(run)
+		runs = append(runs, run)
+	}
+
+	drained := mergeByDue(runs)
+	sortForDelivery(s.order, drained)
+
+	s.fire(drained)
+	s.signalIfEmpty()
+
+	return drained
+}
+
+// mergeByDue does a k-way merge of runs that are each sorted by due time,
+// breaking ties by Id and then in insertion order.
+func mergeByDue[T Schedulable](runs [][]*entry[T]) []*entry[T] {
+	total := 0
+	h := make(runHeap[T], 0, len(runs))
+	for _, run := range runs {
+		if len(run) > 0 {
+			h = append(h, run)
+			total += len(run)
+		}
+	}
+	heap.Init(&h)
+
+	merged := make([]*entry[T], 0, total)
+	for h.Len() > 0 {
+		run := h[0]
+		merged = append(merged, run[0])
+		if len(run) == 1 {
+			heap.Pop(&h)
+		} else {
+			h[0] = run[1:]
+			heap.Fix(&h, 0)
+		}
+	}
+	return merged
+}
+
+// runHeap is a min-heap of sorted runs keyed on their first entry.
+type runHeap[T Schedulable] [][]*entry[T]
+
+func (h runHeap[T]) Len() int {
+	return len(h)
+}
+
+func (h runHeap[T]) Less(i, j int) bool {
+	a, b := h[i][0], h[j][0]
+	if a.due.Equal(b.due) && a.id == b.id {
+		return a.seq < b.seq
+	}
+	return dueBefore(a, b)
+}
+
+func (h runHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *runHeap[T]) Push(x any) {
+	*h = append(*h, x.([]*entry[T]))
+}
+
+func (h *runHeap[T]) Pop() any {
+	old := *h
+	run := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return run
+}
@@ -0,0 +1,100 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDrainDueMergesBuckets(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	for _, ms := range []int{2900, 100, 1500, -400, 2100, 900, 1100, 2500, -900, 1999} {
+		s.AddReminder(testItem{id: fmt.Sprint(ms), due: start.Add(time.Duration(ms) * time.Millisecond)})
+	}
+
+	// Overlap the runs so the merge has to interleave them
+	overlap := testItem{id: "overlap", due: start.Add(50 * time.Millisecond)}
+	s.buckets[1].AddEntity(&entry[testItem]{item: overlap, id: overlap.id, due: overlap.due, seq: 100})
+
+	assertOrdered := func(name string, got []testItem, want int) {
+		t.Helper()
+		if len(got) != want {
+			t.Errorf("%s returned %d items, want %d", name, len(got), want)
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i].due.Before(got[i-1].due) {
+				t.Errorf("%s out of order: %s before %s", name, got[i-1].id, got[i].id)
+			}
+		}
+	}
+
+	assertOrdered("DueBefore", s.DueBefore(start.Add(2*time.Second)), 8)
+
+	clock.Advance(3 * time.Second)
+	assertOrdered("DrainDue", s.DrainDue(), 3)
+
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d after draining everything, want 0", s.Len())
+	}
+}
+
+func TestDrainAll(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[recurringItem](context.Background(), time.Second, 4,
+		WithClock[recurringItem](clock),
+		WithOverflow[recurringItem](),
+	)
+
+	s.AddReminder(recurringItem{testItem{id: "c", due: start.Add(time.Hour)}, 0, 0})
+	s.AddReminder(recurringItem{testItem{id: "a", due: start.Add(-time.Second)}, time.Second, 0})
+	s.AddReminder(recurringItem{testItem{id: "b", due: start.Add(2 * time.Second)}, 0, 0})
+
+	var got []string
+	for _, item := range s.DrainAll() {
+		got = append(got, item.id)
+	}
+	if want := "[a b c]"; fmt.Sprint(got) != want {
+		t.Errorf("DrainAll() = %v, want %s", got, want)
+	}
+	if s.Len() != 0 || s.OverflowLen() != 0 {
+		t.Errorf("Len() = %d, OverflowLen() = %d after DrainAll, want 0", s.Len(), s.OverflowLen())
+	}
+
+	s.AddReminder(recurringItem{testItem{id: "d", due: start}, 0, 0})
+	if due := s.Due(); len(due) != 1 {
+		t.Errorf("Due() = %v after reuse, want [d]", due)
+	}
+}
+
+func TestTakeOverdue(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "overdue-b", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "overdue-a", due: start.Add(-time.Minute)})
+	s.AddReminder(testItem{id: "now", due: start.Add(1200 * time.Millisecond)})
+	s.AddReminder(testItem{id: "future", due: start.Add(2500 * time.Millisecond)})
+
+	clock.Advance(1500 * time.Millisecond)
+
+	var got []string
+	for _, item := range s.TakeOverdue() {
+		got = append(got, item.id)
+	}
+	if want := "[overdue-a overdue-b]"; fmt.Sprint(got) != want {
+		t.Errorf("TakeOverdue() = %v, want %s", got, want)
+	}
+
+	if due := s.Due(); len(due) != 1 || due[0].id != "now" {
+		t.Errorf("Due() = %v, want only the item due now", due)
+	}
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d, want the future item left", s.Len())
+	}
+}
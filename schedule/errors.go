@@ -0,0 +1,18 @@
+package schedule
+
+import "errors"
+
+var (
+	ErrZeroBase        = errors.New("schedule: base time must not be zero")
+	ErrDuplicateId     = errors.New("schedule: an item with this id is already scheduled")
+	ErrBlockSize       = errors.New("schedule: block size must be positive")
+	ErrCapacity        = errors.New("schedule: scheduler is at capacity")
+	ErrZeroDue         = errors.New("schedule: due time must not be zero")
+	ErrNoCodec         = errors.New("schedule: no codec configured")
+	ErrCorrupt         = errors.New("schedule: data is corrupt or from an unknown version")
+	ErrTailFull        = errors.New("schedule: too many items are waiting beyond the horizon")
+	ErrTooFar          = errors.New("schedule: due time is too far in the future")
+	ErrLayout          = errors.New("schedule: layout windows must be non-empty and contiguous")
+	ErrGrowthExhausted = errors.New("schedule: due time is beyond the largest horizon allowed")
+	ErrTxDone          = errors.New("schedule: transaction has already been committed or rolled back")
+)
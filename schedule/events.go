@@ -0,0 +1,136 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+type EventKind int
+
+const (
+	Added EventKind = iota
+	Removed
+	Fired
+	// Completed follows the Fired event for the last occurrence of a
+	// LimitedRecurring item.
+	Completed
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Fired:
+		return "fired"
+	case Completed:
+		return "completed"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a single change to the set of scheduled items.
+type Event[T Schedulable] struct {
+	Kind EventKind
+	Item T
+}
+
+const eventBuffer = 1024
+
+// Events returns a feed of every item added to, removed from or fired by the
+// scheduler. The channel is buffered; if the consumer falls more than the
+// buffer behind, further events are dropped rather than blocking the
+// scheduler. It is closed once the scheduler's context is done.
+func (s *Scheduler[T]) Events() <-chan Event[T] {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.events == nil {
+		s.events = make(chan Event[T], eventBuffer)
+		if done := s.ctx.Done(); done != nil {
+			go func() {
+				<-done
+				s.mutex.Lock()
+				defer s.mutex.Unlock()
+				close(s.events)
+				s.eventsClosed = true
+			}()
+		}
+	}
+
+	return s.events
+}
+
+// BatchEvents is Events with the events coalesced into slices of up to
+// maxBatch, each sent once it is full or, if it isn't empty, every flush as
+// timed by the scheduler's clock. Events stay in the order they happened,
+// both within a batch and from one batch to the next. It reads from the same
+// feed as Events, so use one or the other. The channel is closed, after a
+// final flush, once the scheduler's context is done, or straight away, with
+// anything not yet sent discarded, once ctx is.
+func (s *Scheduler[T]) BatchEvents(ctx context.Context, maxBatch int, flush time.Duration) <-chan []Event[T] {
+	if maxBatch < 1 {
+		maxBatch = 1
+	}
+
+	events := s.Events()
+	out := make(chan []Event[T])
+	go func() {
+		defer close(out)
+
+		timer := s.clock.NewTimer(flush)
+		defer timer.Stop()
+
+		batch := make([]Event[T], 0, maxBatch)
+		send := func() bool {
+			if len(batch) == 0 {
+				return true
+			}
+			select {
+			case out <- batch:
+				batch = make([]Event[T], 0, maxBatch)
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					send()
+					return
+				}
+				batch = append(batch, event)
+				if len(batch) == maxBatch && !send() {
+					return
+				}
+			case <-timer.C():
+				if !send() {
+					return
+				}
+				timer.Reset(flush)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// emit publishes an event without blocking. It must be called with the mutex
+// held.
+func (s *Scheduler[T]) emit(kind EventKind, entity *entry[T]) {
+	if s.events == nil || s.eventsClosed {
+		return
+	}
+
+	select {
+	case s.events <- Event[T]{Kind: kind, Item: entity.item}:
+	default:
+	}
+}
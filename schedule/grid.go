@@ -0,0 +1,40 @@
+package schedule
+
+import "time"
+
+// WithDueGrid rounds the due time of every item added up to the next multiple
+// of d, counted from the zero time, so items due close together come due at
+// exactly the same moment and can be handled as a batch. Items are never made
+// due earlier than asked, only up to d later. Their own DueTime is left
+// alone; recurring items keep their interval from the rounded time.
+func WithDueGrid[T Schedulable](d time.Duration) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.dueGrid = d
+	}
+}
+
+// AddReminderSnapped is AddReminderAt that also reports whether WithDueGrid
+// moved the item's due time.
+func (s *Scheduler[T]) AddReminderSnapped(entity T) (int, bool) {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	s.update()
+
+	e := newEntry(entity)
+	idx, err := s.tryAdd(e)
+	if err != nil {
+		return Rejected, false
+	}
+	return idx, !e.due.Equal(entity.DueTime())
+}
+
+// snap rounds entity's due time up onto the WithDueGrid grid.
+func (s *Scheduler[T]) snap(entity *entry[T]) {
+	if s.dueGrid <= 0 || entity.due.IsZero() {
+		return
+	}
+	if snapped := entity.due.Truncate(s.dueGrid); snapped.Before(entity.due) {
+		entity.due = snapped.Add(s.dueGrid)
+	}
+}
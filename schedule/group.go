@@ -0,0 +1,60 @@
+package schedule
+
+// Grouped is implemented by items that belong to a group whose members must
+// be delivered together. Due holds back every member of a group until all of
+// its pending members are due, then releases them in one batch, so a group
+// spread over several buckets fires when its latest member comes due.
+// Cancelling or expiring a member removes it from the group; the rest are
+// released without it. An empty GroupId means the item isn't grouped.
+//
+// Grouping is honoured by Due, DueLimit and Start. A DueLimit smaller than a
+// released group hands the rest of it out on the next call, and other ways of
+// taking items (DrainDue, DuePeek, CatchUp and so on) deliver members
+// individually.
+type Grouped interface {
+	GroupId() string
+}
+
+// join records that entity is now pending.
+func (s *Scheduler[T]) join(entity *entry[T]) {
+	s.metrics.depth.Add(1)
+	if entity.group != "" {
+		if s.groups == nil {
+			s.groups = make(map[string]int)
+		}
+		s.groups[entity.group]++
+	}
+}
+
+// leave records that entity is no longer pending.
+func (s *Scheduler[T]) leave(entity *entry[T]) {
+	s.metrics.depth.Add(-1)
+	if entity.group != "" {
+		if s.groups[entity.group]--; s.groups[entity.group] <= 0 {
+			delete(s.groups, entity.group)
+		}
+	}
+}
+
+// holdGroups filters due down to the ungrouped entries and those whose whole
+// group is due.
+func (s *Scheduler[T]) holdGroups(due []*entry[T]) []*entry[T] {
+	if len(s.groups) == 0 {
+		return due
+	}
+
+	ready := make(map[string]int)
+	for _, entity := range due {
+		if entity.group != "" {
+			ready[entity.group]++
+		}
+	}
+
+	released := due[:0]
+	for _, entity := range due {
+		if entity.group == "" || ready[entity.group] == s.groups[entity.group] {
+			released = append(released, entity)
+		}
+	}
+	return released
+}
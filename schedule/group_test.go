@@ -0,0 +1,68 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type groupedItem struct {
+	testItem
+	group string
+}
+
+func (g groupedItem) GroupId() string {
+	return g.group
+}
+
+func dueIds(items []groupedItem) string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.id)
+	}
+	return fmt.Sprint(ids)
+}
+
+func TestGroupedReleasedTogether(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[groupedItem](context.Background(), time.Second, 4, WithClock[groupedItem](clock))
+
+	s.AddReminder(groupedItem{testItem{id: "a", due: start.Add(100 * time.Millisecond)}, "g"})
+	s.AddReminder(groupedItem{testItem{id: "b", due: start.Add(200 * time.Millisecond)}, ""})
+	s.AddReminder(groupedItem{testItem{id: "c", due: start.Add(2500 * time.Millisecond)}, "g"})
+
+	clock.Advance(time.Second)
+	if got := dueIds(s.Due()); got != "[b]" {
+		t.Errorf("Due() = %s before the whole group is due, want [b]", got)
+	}
+
+	clock.Advance(2 * time.Second)
+	if got := dueIds(s.Due()); got != "[a c]" {
+		t.Errorf("Due() = %s once the group is due, want [a c]", got)
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", s.Len())
+	}
+}
+
+func TestGroupedMemberCancelled(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[groupedItem](context.Background(), time.Second, 4, WithClock[groupedItem](clock))
+
+	s.AddReminder(groupedItem{testItem{id: "a", due: start.Add(100 * time.Millisecond)}, "g"})
+	s.AddReminder(groupedItem{testItem{id: "b", due: start.Add(200 * time.Millisecond)}, "g"})
+	s.AddReminder(groupedItem{testItem{id: "c", due: start.Add(3 * time.Second)}, "g"})
+
+	clock.Advance(time.Second)
+	if got := dueIds(s.Due()); got != "[]" {
+		t.Errorf("Due() = %s, want []", got)
+	}
+
+	s.Cancel("c")
+	if got := dueIds(s.Due()); got != "[a b]" {
+		t.Errorf("Due() = %s after cancelling the last member, want [a b]", got)
+	}
+}
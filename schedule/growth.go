@@ -0,0 +1,109 @@
+package schedule
+
+import "time"
+
+// GrowthExhausted decides what happens to an item added beyond the horizon
+// once WithMaxBlocks won't let it grow any further.
+type GrowthExhausted int
+
+const (
+	// Clamp schedules the item in the last bucket, or the overflow, as if
+	// the horizon couldn't grow at all.
+	Clamp GrowthExhausted = iota
+	// Reject refuses the item with ErrGrowthExhausted.
+	Reject
+	// DeadLetter refuses the item with ErrGrowthExhausted and also passes it
+	// to WithDeadLetter as BeyondHorizon.
+	DeadLetter
+)
+
+// WithMaxBlocks lets the horizon grow on demand: an item added beyond the
+// last bucket gets new buckets appended to cover it, up to n buckets in all,
+// instead of being clamped into the last one. The extra buckets are not
+// replaced as they rotate away, so the horizon shrinks back to numBlocks.
+// WithGrowthExhausted decides what happens past n. The default is no growth.
+func WithMaxBlocks[T Schedulable](n int) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.maxBlocks = n
+	}
+}
+
+// WithGrowthExhausted sets what happens to items added beyond the furthest
+// horizon WithMaxBlocks allows. The default is Clamp.
+func WithGrowthExhausted[T Schedulable](policy GrowthExhausted) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.growthExhausted = policy
+	}
+}
+
+// exhausted reports whether an item due at due is beyond even the furthest
+// horizon WithMaxBlocks allows.
+func (s *Scheduler[T]) exhausted(due time.Time) bool {
+	if s.maxBlocks <= 0 {
+		return false
+	}
+	spare := s.maxBlocks - len(s.buckets)
+	if spare < 0 {
+		spare = 0
+	}
+	limit := s.buckets[len(s.buckets)-1].endTime.Add(time.Duration(spare) * s.blockSize)
+	return !due.Before(limit)
+}
+
+// canGrow reports whether growing the horizon would bring an item due at due
+// within it.
+func (s *Scheduler[T]) canGrow(due time.Time) bool {
+	return s.maxBlocks > 0 && !s.exhausted(due)
+}
+
+// grow appends buckets until due is within the horizon or there are
+// WithMaxBlocks of them.
+func (s *Scheduler[T]) grow(due time.Time) {
+	if s.maxBlocks <= 0 || !s.beyondHorizon(due) {
+		return
+	}
+
+	if s.exhausted(due) {
+		s.logger.Log(LevelWarn, "horizon growth exhausted", "due", due, "buckets", len(s.buckets), "policy", s.growthExhausted)
+	}
+
+	grown := 0
+	for len(s.buckets) < s.maxBlocks && s.beyondHorizon(due) {
+		start := s.buckets[len(s.buckets)-1].endTime
+		s.appendBucket(start, start.Add(s.blockSize))
+		grown++
+	}
+	if grown > 0 {
+		s.logger.Log(LevelDebug, "grew horizon", "buckets", grown, "end", s.buckets[len(s.buckets)-1].endTime)
+		// Items clamped into the old last bucket, or waiting in the
+		// overflow, may belong in one of the new buckets
+		last := s.buckets[len(s.buckets)-grown-1]
+		for _, entity := range last.removeWhere(func(e *entry[T]) bool { return !e.due.Before(last.endTime) }) {
+			s.place(entity)
+		}
+		s.migrateOverflow()
+	}
+}
+
+// reschedule puts back an entity that is already scheduled but has been taken
+// out of its bucket, because its due time changed or the buckets were
+// rebuilt, growing the horizon to reach it as tryAdd would. It is kept even
+// if it is beyond the furthest horizon WithMaxBlocks allows, clamped or in
+// the overflow, since it has nowhere else to go; callers that can leave it
+// where it was check refuses first.
+func (s *Scheduler[T]) reschedule(entity *entry[T]) int {
+	s.grow(entity.due)
+	return s.place(entity)
+}
+
+// refuses returns why WithGrowthExhausted or WithMaxTailDepth won't let an
+// item already scheduled move to due, if they won't.
+func (s *Scheduler[T]) refuses(due time.Time) error {
+	if s.growthExhausted != Clamp && s.exhausted(due) {
+		return ErrGrowthExhausted
+	}
+	if s.tailFull(due) {
+		return ErrTailFull
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package schedule
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGrowth(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock), WithMaxBlocks[testItem](6))
+
+	s.AddReminder(testItem{id: "clamped", due: start.Add(time.Minute)})
+	if idx := s.AddReminderAt(testItem{id: "a", due: start.Add(5500 * time.Millisecond)}); idx != 5 {
+		t.Errorf("AddReminderAt(+5.5s) = %d, want bucket 5 after growing", idx)
+	}
+	if occupancy := s.Occupancy(); len(occupancy) != 6 || occupancy[5] != 2 {
+		t.Errorf("Occupancy() = %v, want 6 buckets with a and the clamped item in the last", occupancy)
+	}
+	if err := s.Check(); err != nil {
+		t.Errorf("Check() after growing = %v", err)
+	}
+
+	// The extra buckets aren't replaced as they rotate away
+	clock.Advance(3 * time.Second)
+	if occupancy := s.Occupancy(); len(occupancy) != 4 {
+		t.Errorf("Occupancy() = %v after rotating, want 4 buckets", occupancy)
+	}
+}
+
+func TestGrowthExhausted(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	for _, policy := range []GrowthExhausted{Clamp, Reject, DeadLetter} {
+		var lost []Reason
+		s := NewScheduler[testItem](context.Background(), time.Second, 4,
+			WithClock[testItem](clock),
+			WithMaxBlocks[testItem](6),
+			WithGrowthExhausted[testItem](policy),
+			WithDeadLetter(func(item testItem, reason Reason) { lost = append(lost, reason) }),
+		)
+
+		// Just inside the furthest horizon is always fine
+		if idx := s.AddReminderAt(testItem{id: "inside", due: start.Add(6*time.Second - 1)}); idx != 5 {
+			t.Errorf("policy %d: AddReminderAt(6s-1ns) = %d, want bucket 5", policy, idx)
+		}
+
+		err := s.AddReminder(testItem{id: "beyond", due: start.Add(6 * time.Second)})
+		switch policy {
+		case Clamp:
+			if err != nil {
+				t.Errorf("Clamp: AddReminder beyond the cap = %v, want nil", err)
+			}
+			if occupancy := s.Occupancy(); len(occupancy) != 6 || occupancy[5] != 2 {
+				t.Errorf("Clamp: Occupancy() = %v, want the item clamped into bucket 5", occupancy)
+			}
+		case Reject, DeadLetter:
+			if err != ErrGrowthExhausted {
+				t.Errorf("policy %d: AddReminder beyond the cap = %v, want ErrGrowthExhausted", policy, err)
+			}
+			if n := s.Len(); n != 1 {
+				t.Errorf("policy %d: Len() = %d, want 1", policy, n)
+			}
+		}
+
+		if policy == DeadLetter {
+			if len(lost) != 1 || lost[0] != BeyondHorizon {
+				t.Errorf("DeadLetter: dead letters = %v, want [beyond horizon]", lost)
+			}
+		} else if len(lost) != 0 {
+			t.Errorf("policy %d: dead letters = %v, want none", policy, lost)
+		}
+	}
+}
+
+func TestGrowthDefer(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 2,
+		WithClock[testItem](clock),
+		WithMaxBlocks[testItem](4),
+		WithGrowthExhausted[testItem](Reject),
+	)
+
+	s.AddReminder(testItem{id: "a", due: start.Add(1200 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(1400 * time.Millisecond)})
+	if n := s.Defer(1, 2*time.Second); n != 2 {
+		t.Fatalf("Defer() = %d, want 2", n)
+	}
+	if occupancy := s.Occupancy(); !reflect.DeepEqual(occupancy, []int{0, 0, 0, 2}) {
+		t.Errorf("Occupancy() = %v, want the horizon grown to reach the deferred items", occupancy)
+	}
+
+	// Deferring them beyond the cap is refused, so they stay where they were
+	if n := s.Defer(3, 2*time.Second); n != 0 {
+		t.Errorf("Defer() beyond the cap = %d, want 0", n)
+	}
+	if d, _ := s.TimeUntil("a"); d != 3200*time.Millisecond {
+		t.Errorf("a is due in %s after a refused Defer, want 3.2s", d)
+	}
+}
+
+func TestGrowthShift(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock), WithMaxBlocks[testItem](8))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(6500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(1500 * time.Millisecond)})
+	s.Shift(0)
+	if occupancy := s.Occupancy(); !reflect.DeepEqual(occupancy, []int{0, 1, 0, 0, 0, 0, 1}) {
+		t.Errorf("Occupancy() = %v after Shift, want the grown buckets kept for a", occupancy)
+	}
+
+	if err := s.SetResolution(500 * time.Millisecond); err != nil {
+		t.Fatalf("SetResolution() = %v", err)
+	}
+	if occupancy := s.Occupancy(); len(occupancy) != 8 || occupancy[7] != 1 {
+		t.Errorf("Occupancy() = %v after SetResolution, want a clamped into the last of 8 buckets", occupancy)
+	}
+}
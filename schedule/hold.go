@@ -0,0 +1,35 @@
+package schedule
+
+// Hold quarantines every scheduled item matching pred and returns how many
+// were newly held, e.g. while the dependency they need is down. Held items
+// stay scheduled, and still count towards Len, but nothing delivers, drops or
+// defers them and they don't count as overdue until they are released.
+func (s *Scheduler[T]) Hold(pred func(T) bool) int {
+	return s.setHeld(pred, true)
+}
+
+// Release lets the held items matching pred be delivered again, as soon as
+// they are due, and returns how many were released. They never left their
+// buckets, so there is nothing to re-bin.
+func (s *Scheduler[T]) Release(pred func(T) bool) int {
+	return s.setHeld(pred, false)
+}
+
+func (s *Scheduler[T]) setHeld(pred func(T) bool, held bool) int {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	changed := 0
+	for _, bucket := range s.stores() {
+		for _, entity := range bucket.elements {
+			if entity.held != held && pred(entity.item) {
+				entity.held = held
+				changed++
+			}
+		}
+	}
+
+	s.logger.Log(LevelInfo, "changed held items", "held", held, "count", changed)
+	return changed
+}
@@ -0,0 +1,76 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHold(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "db:overdue", due: start.Add(-time.Minute)})
+	s.AddReminder(testItem{id: "db:soon", due: start.Add(1500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "web:overdue", due: start.Add(-time.Minute)})
+	s.AddReminder(testItem{id: "web:soon", due: start.Add(1500 * time.Millisecond)})
+
+	usesDB := func(item testItem) bool {
+		return strings.HasPrefix(item.id, "db:")
+	}
+	if n := s.Hold(usesDB); n != 2 {
+		t.Errorf("Hold() = %d, want 2", n)
+	}
+	if n := s.OverdueCount(); n != 1 {
+		t.Errorf("OverdueCount() = %d with one overdue item held, want 1", n)
+	}
+
+	ids := func(items []testItem) string {
+		var got []string
+		for _, item := range items {
+			got = append(got, item.id)
+		}
+		return fmt.Sprint(got)
+	}
+
+	clock.Advance(2 * time.Second)
+	if got := ids(s.Due()); got != "[web:overdue web:soon]" {
+		t.Errorf("Due() = %s while db items are held, want only web items", got)
+	}
+	if got := ids(s.DrainDue()); got != "[]" {
+		t.Errorf("DrainDue() = %s while db items are held, want nothing", got)
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d, want the 2 held items", s.Len())
+	}
+
+	if n := s.Release(usesDB); n != 2 {
+		t.Errorf("Release() = %d, want 2", n)
+	}
+	if got := ids(s.Due()); got != "[db:overdue db:soon]" {
+		t.Errorf("Due() = %s after releasing, want the db items", got)
+	}
+}
+
+func TestHoldDefer(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "held", due: start.Add(1500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "free", due: start.Add(1500 * time.Millisecond)})
+	s.Hold(func(item testItem) bool { return item.id == "held" })
+
+	if n := s.Defer(1, time.Second); n != 1 {
+		t.Errorf("Defer() = %d, want only the item that isn't held moved", n)
+	}
+	if d, _ := s.TimeUntil("held"); d != 1500*time.Millisecond {
+		t.Errorf("held item is due in %s after Defer, want 1.5s", d)
+	}
+	if d, _ := s.TimeUntil("free"); d != 2500*time.Millisecond {
+		t.Errorf("free item is due in %s after Defer, want 2.5s", d)
+	}
+}
@@ -0,0 +1,32 @@
+package schedule
+
+import "context"
+
+// Iter streams every pending item, in due-time order, on the returned channel
+// and closes it when done or when ctx is cancelled. The items are snapshotted
+// under the lock and the lock is released before streaming starts, so a slow
+// consumer never blocks the scheduler; the stream is a point-in-time view and
+// won't reflect items added or removed afterwards.
+func (s *Scheduler[T]) Iter(ctx context.Context) <-chan T {
+	s.mutex.Lock()
+	s.update()
+	entries := s.entries()
+	s.unlock()
+
+	sortByDue(entries)
+	snapshot := items(entries)
+
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, item := range snapshot {
+			select {
+			case out <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,55 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIter(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	for _, id := range []string{"c", "a", "b"} {
+		s.AddReminder(testItem{id: id, due: start.Add(time.Duration(id[0]-'a') * time.Second)})
+	}
+
+	stream := s.Iter(context.Background())
+
+	// The scheduler stays usable while the stream is being consumed, and
+	// changes made now don't show up in it
+	s.AddReminder(testItem{id: "d", due: start.Add(500 * time.Millisecond)})
+
+	var got []string
+	for item := range stream {
+		got = append(got, item.id)
+	}
+	if want := "[a b c]"; fmt.Sprint(got) != want {
+		t.Errorf("Iter() streamed %v, want %s", got, want)
+	}
+}
+
+func TestIterCancel(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	for i := 0; i < 10; i++ {
+		s.AddReminder(testItem{id: fmt.Sprint(i), due: start.Add(time.Second)})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := s.Iter(ctx)
+	<-stream
+	cancel()
+
+	count := 0
+	for range stream {
+		count++
+	}
+	if count > 1 {
+		t.Errorf("received %d items after cancelling, want at most 1", count)
+	}
+}
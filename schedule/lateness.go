@@ -0,0 +1,68 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+)
+
+// WithMaxLateness drops, rather than delivers, any item whose due time is
+// more than d in the past, such as reminders that went stale during an outage
+// or while the scheduler was paused. It applies to every item, whether or not
+// it is Expiring. Items are dropped as the scheduler updates, before anything
+// can take them.
+func WithMaxLateness[T Schedulable](d time.Duration) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.maxLateness = d
+	}
+}
+
+// WithOnDrop registers fn to be called with every item dropped because of
+// WithMaxLateness.
+func WithOnDrop[T Schedulable](fn func(T)) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.onDrop = fn
+	}
+}
+
+// dropLate removes every item, other than held ones, that is later than the
+// scheduler's maximum lateness. They are all overdue, so they are in the
+// head bucket.
+func (s *Scheduler[T]) dropLate(now time.Time) {
+	if s.maxLateness <= 0 {
+		return
+	}
+
+	cutoff := now.Add(-s.maxLateness)
+	head := s.buckets[0]
+	n := sort.Search(len(head.elements), func(i int) bool {
+		return !head.elements[i].due.Before(cutoff)
+	})
+	if n == 0 {
+		return
+	}
+
+	late := head.removeWhere(func(e *entry[T]) bool {
+		return e.due.Before(cutoff) && !e.held
+	})
+	for _, entity := range late {
+		s.drop(entity)
+	}
+	s.signalIfEmpty()
+}
+
+func (s *Scheduler[T]) drop(entity *entry[T]) {
+	s.logger.Log(LevelDebug, "dropped late item", "id", entity.id, "due", entity.due)
+	s.metrics.dropped.Add(1)
+	s.landed(entity)
+	s.leave(entity)
+	s.untrack(entity)
+	s.emit(Removed, entity)
+	s.trace(TraceDropped, entity)
+	s.lost(entity, TooLate)
+	if s.onDrop != nil {
+		onDrop := s.onDrop
+		s.later(func() {
+			onDrop(entity.item)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMaxLateness(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	var dropped []string
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithMaxLateness[testItem](time.Minute),
+		WithOnDrop(func(item testItem) {
+			dropped = append(dropped, item.id)
+		}),
+	)
+
+	s.AddReminder(testItem{id: "stale", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "fresh", due: start.Add(2 * time.Minute)})
+
+	// Nothing is dropped while paused, but the item goes stale all the same
+	s.Pause()
+	clock.Advance(90 * time.Second)
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d while paused, want 2", s.Len())
+	}
+	s.Resume()
+
+	if len(dropped) != 1 || dropped[0] != "stale" {
+		t.Errorf("dropped %v, want [stale]", dropped)
+	}
+	if n := s.TotalDropped(); n != 1 {
+		t.Errorf("TotalDropped() = %d, want 1", n)
+	}
+
+	clock.Advance(time.Minute)
+	due := s.Due()
+	if len(due) != 1 || due[0].id != "fresh" {
+		t.Errorf("Due() = %v, want only fresh", due)
+	}
+}
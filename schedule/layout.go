@@ -0,0 +1,82 @@
+package schedule
+
+import "time"
+
+// Layout is a scheduler's time grid: its bucket width, how many buckets it
+// keeps, and the [start, end) window of each current bucket, soonest first.
+// It has no items in it, and its fields are exported so it can be stored
+// however suits.
+type Layout struct {
+	BlockSize time.Duration
+	NumBlocks int
+	Windows   [][2]time.Time
+}
+
+// ExportLayout returns the scheduler's current bucket layout, so that a
+// restarted process can carry on with the same grid with ImportLayout.
+func (s *Scheduler[T]) ExportLayout() Layout {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	windows := make([][2]time.Time, len(s.buckets))
+	for i, bucket := range s.buckets {
+		windows[i] = [2]time.Time{bucket.startTime.Round(0), bucket.endTime.Round(0)}
+	}
+	return Layout{BlockSize: s.blockSize, NumBlocks: s.numBlocks, Windows: windows}
+}
+
+// ImportLayout replaces the scheduler's buckets with the ones described by
+// layout and re-bins everything scheduled into them, then brings them up to
+// date with the clock as usual, which keeps them on the same grid. If there
+// are fewer windows than NumBlocks, buckets of BlockSize are added after the
+// last one to make up the difference. It returns ErrBlockSize or ErrLayout,
+// and changes nothing, if layout is invalid: each window must be non-empty
+// and begin where the one before it ends.
+func (s *Scheduler[T]) ImportLayout(layout Layout) error {
+	if err := layout.validate(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.unlock()
+
+	pending := s.entries()
+
+	s.blockSize = layout.BlockSize
+	s.numBlocks = layout.NumBlocks
+	s.buckets = make([]*TimespanBucket[*entry[T]], 0, len(layout.Windows))
+	for _, window := range layout.Windows {
+		s.appendBucket(window[0], window[1])
+	}
+	// A layout with fewer windows than NumBlocks is carried on with blocks
+	// of BlockSize, as the horizon would be
+	s.extend(s.buckets[len(s.buckets)-1].endTime)
+	s.clearOverflow()
+	for _, entity := range pending {
+		s.reschedule(entity)
+	}
+	s.headChanged()
+
+	s.logger.Log(LevelInfo, "imported layout", "start", layout.Windows[0][0], "buckets", len(layout.Windows), "items", len(pending))
+	s.update()
+	return nil
+}
+
+func (l Layout) validate() error {
+	if l.BlockSize <= 0 {
+		return ErrBlockSize
+	}
+	if l.NumBlocks < 1 || len(l.Windows) == 0 {
+		return ErrLayout
+	}
+	for i, window := range l.Windows {
+		if !window[0].Before(window[1]) {
+			return ErrLayout
+		}
+		if i > 0 && !window[0].Equal(l.Windows[i-1][1]) {
+			return ErrLayout
+		}
+	}
+	return nil
+}
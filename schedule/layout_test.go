@@ -0,0 +1,87 @@
+package schedule
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLayoutRoundTrip(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	before, err := NewSchedulerAt[testItem](context.Background(), start.Add(250*time.Millisecond), time.Second, 4, WithClock[testItem](clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(1500 * time.Millisecond)
+	layout := before.ExportLayout()
+	if len(layout.Windows) != 4 || !layout.Windows[0][0].Equal(start.Add(1250*time.Millisecond)) {
+		t.Fatalf("ExportLayout() = %v, want 4 windows from 11:00:01.25", layout.Windows)
+	}
+
+	// A restarted process would otherwise lay its grid out from its own now
+	clock.Advance(2200 * time.Millisecond)
+	after := NewScheduler[testItem](context.Background(), time.Minute, 2, WithClock[testItem](clock))
+	after.AddReminder(testItem{id: "a", due: start.Add(5 * time.Second)})
+
+	if err := after.ImportLayout(layout); err != nil {
+		t.Fatalf("ImportLayout() = %v", err)
+	}
+	got := after.ExportLayout()
+	if got.BlockSize != time.Second || got.NumBlocks != 4 || len(got.Windows) != 4 {
+		t.Fatalf("ExportLayout() after import = %+v, want four 1s buckets", got)
+	}
+	if want := start.Add(3250 * time.Millisecond); !got.Windows[0][0].Equal(want) {
+		t.Errorf("head starts at %s after import, want %s on the old grid", got.Windows[0][0], want)
+	}
+	if occupancy := after.Occupancy(); !reflect.DeepEqual(occupancy, []int{0, 1, 0, 0}) {
+		t.Errorf("Occupancy() = %v, want a re-binned into bucket 1", occupancy)
+	}
+}
+
+func TestImportLayoutInvalid(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](NewFakeClock(start)))
+	want := s.ExportLayout()
+
+	window := func(from, to int) [2]time.Time {
+		return [2]time.Time{start.Add(time.Duration(from) * time.Second), start.Add(time.Duration(to) * time.Second)}
+	}
+	for _, tc := range []struct {
+		name   string
+		layout Layout
+		err    error
+	}{
+		{"gap", Layout{time.Second, 2, [][2]time.Time{window(0, 1), window(2, 3)}}, ErrLayout},
+		{"overlap", Layout{time.Second, 2, [][2]time.Time{window(0, 2), window(1, 3)}}, ErrLayout},
+		{"empty window", Layout{time.Second, 2, [][2]time.Time{window(0, 1), window(1, 1)}}, ErrLayout},
+		{"no windows", Layout{time.Second, 2, nil}, ErrLayout},
+		{"zero block size", Layout{0, 2, [][2]time.Time{window(0, 1)}}, ErrBlockSize},
+	} {
+		if err := s.ImportLayout(tc.layout); err != tc.err {
+			t.Errorf("%s: ImportLayout() = %v, want %v", tc.name, err, tc.err)
+		}
+	}
+
+	if got := s.ExportLayout(); got.BlockSize != want.BlockSize || !got.Windows[0][0].Equal(want.Windows[0][0]) {
+		t.Errorf("layout changed to %+v after rejected imports", got)
+	}
+}
+
+func TestImportLayoutFewerWindows(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	s := NewScheduler[testItem](context.Background(), time.Minute, 2, WithClock[testItem](NewFakeClock(start)))
+	s.AddReminder(testItem{id: "a", due: start.Add(3500 * time.Millisecond)})
+
+	layout := Layout{time.Second, 4, [][2]time.Time{{start, start.Add(time.Second)}, {start.Add(time.Second), start.Add(2 * time.Second)}}}
+	if err := s.ImportLayout(layout); err != nil {
+		t.Fatalf("ImportLayout() = %v", err)
+	}
+	if err := s.Check(); err != nil {
+		t.Errorf("Check() = %v after importing 2 of 4 windows", err)
+	}
+	if occupancy := s.Occupancy(); !reflect.DeepEqual(occupancy, []int{0, 0, 0, 1}) {
+		t.Errorf("Occupancy() = %v, want a in the last of four buckets", occupancy)
+	}
+}
@@ -0,0 +1,41 @@
+package schedule
+
+import "time"
+
+// WithWindowLoader makes the scheduler a sliding window over an external
+// store: loader is called with the [start, end) of every bucket as it enters
+// the horizon, including the initial buckets and any added by WithMaxBlocks
+// growth, and whatever it returns is added. A scheduler never asks for the
+// same time twice, and windows skipped because the whole horizon passed at
+// once are not loaded. The same window may be asked for again by a new
+// scheduler after a restart, so loads should be idempotent or combined with
+// WithDedupe(DedupeReject). loader is called with the lock held and must not
+// call back into the scheduler.
+func WithWindowLoader[T Schedulable](loader func(start, end time.Time) []T) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.loader = loader
+	}
+}
+
+// load calls the window loader for whatever part of [start, end) it hasn't
+// already been asked for, so a window that is rebuilt, e.g. by CatchUp or
+// ImportLayout, isn't loaded twice.
+func (s *Scheduler[T]) load(start, end time.Time) {
+	if start.Before(s.loadedUntil) {
+		start = s.loadedUntil
+	}
+	if !start.Before(end) {
+		return
+	}
+	s.loadedUntil = end
+
+	if s.loader == nil {
+		return
+	}
+
+	loaded := s.loader(start, end)
+	for _, item := range loaded {
+		s.tryAdd(newEntry(item))
+	}
+	s.logger.Log(LevelDebug, "loaded window", "start", start, "end", end, "items", len(loaded))
+}
@@ -0,0 +1,86 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWindowLoader(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	// The backing store has one item at the middle of every second
+	var loads []string
+	loader := func(from, to time.Time) []testItem {
+		loads = append(loads, fmt.Sprint(from.Sub(start)))
+		return []testItem{{id: fmt.Sprint(from.Sub(start)), due: from.Add(500 * time.Millisecond)}}
+	}
+	s := NewScheduler[testItem](context.Background(), time.Second, 2,
+		WithClock[testItem](clock),
+		WithWindowLoader(loader),
+	)
+
+	if want := "[0s 1s]"; fmt.Sprint(loads) != want {
+		t.Errorf("loaded %v on creation, want %s", loads, want)
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", s.Len())
+	}
+
+	clock.Advance(time.Second)
+	if due := s.Due(); len(due) != 1 || due[0].id != "0s" {
+		t.Errorf("Due() = %v, want the item loaded for [0s, 1s)", due)
+	}
+	if want := "[0s 1s 2s]"; fmt.Sprint(loads) != want {
+		t.Errorf("loaded %v after rotating, want %s", loads, want)
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", s.Len())
+	}
+}
+
+func TestWindowLoaderNewBuckets(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	// The windows don't all start on the second here, so only return the
+	// item for a window that holds it
+	var loads []string
+	loader := func(from, to time.Time) []testItem {
+		loads = append(loads, fmt.Sprint(from.Sub(start)))
+		due := from.Truncate(time.Second).Add(500 * time.Millisecond)
+		if due.Before(from) || !due.Before(to) {
+			return nil
+		}
+		return []testItem{{id: fmt.Sprint(from.Sub(start)), due: due}}
+	}
+	s := NewScheduler[testItem](context.Background(), time.Second, 2,
+		WithClock[testItem](clock),
+		WithWindowLoader(loader),
+		WithMaxBlocks[testItem](10),
+	)
+
+	// Growing the horizon to reach the new item loads every window it adds
+	s.AddReminder(testItem{id: "far", due: start.Add(4500 * time.Millisecond)})
+	if want := "[0s 1s 2s 3s 4s]"; fmt.Sprint(loads) != want {
+		t.Errorf("loaded %v after growing, want %s", loads, want)
+	}
+	if s.Len() != 6 {
+		t.Errorf("Len() = %d, want 6", s.Len())
+	}
+
+	// CatchUp rebuilds the horizon from now, growing it again to reach far,
+	// and only loads what is new to it
+	clock.Advance(1200 * time.Millisecond)
+	if due := s.CatchUp(); len(due) != 1 || due[0].id != "0s" {
+		t.Errorf("CatchUp() = %v, want the item loaded for [0s, 1s)", due)
+	}
+	if want := "[0s 1s 2s 3s 4s 5s]"; fmt.Sprint(loads) != want {
+		t.Errorf("loaded %v after CatchUp, want %s", loads, want)
+	}
+	if s.Len() != 5 {
+		t.Errorf("Len() = %d, want 5", s.Len())
+	}
+}
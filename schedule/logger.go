@@ -0,0 +1,45 @@
+package schedule
+
+// Logger receives structured debug output about the scheduler's internal
+// decisions. kv holds alternating keys and values. Log is called while the
+// scheduler's lock is held, so it must not call back into the scheduler.
+type Logger interface {
+	Log(level, msg string, kv ...any)
+}
+
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Log(string, string, ...any) {}
+
+// WithLogger sends the scheduler's internal logging to logger. By default it
+// is discarded.
+func WithLogger[T Schedulable](logger Logger) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.logger = logger
+	}
+}
+
+// WithName names the scheduler so that several can be told apart. The name
+// is shown by String and added to every log line as a "scheduler" key. The
+// default is no name.
+func WithName[T Schedulable](name string) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.name = name
+	}
+}
+
+// namedLogger tags everything logged with the scheduler's name.
+type namedLogger struct {
+	name   string
+	logger Logger
+}
+
+func (l namedLogger) Log(level, msg string, kv ...any) {
+	l.logger.Log(level, msg, append([]any{"scheduler", l.name}, kv...)...)
+}
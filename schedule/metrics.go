@@ -0,0 +1,53 @@
+package schedule
+
+import "sync/atomic"
+
+// metrics are kept with atomics so they can be scraped without contending
+// for the scheduler's lock.
+type metrics struct {
+	scheduled atomic.Uint64
+	fired     atomic.Uint64
+	cancelled atomic.Uint64
+	expired   atomic.Uint64
+	dropped   atomic.Uint64
+	depth     atomic.Int64
+}
+
+// TotalScheduled returns how many items have ever been added.
+func (s *Scheduler[T]) TotalScheduled() uint64 {
+	return s.metrics.scheduled.Load()
+}
+
+// TotalFired returns how many times an item has been delivered. Each
+// occurrence of a recurring item counts.
+func (s *Scheduler[T]) TotalFired() uint64 {
+	return s.metrics.fired.Load()
+}
+
+// TotalCancelled returns how many items were taken out undelivered on
+// purpose: by Cancel, RemoveWhere or a committed Tx, by being replaced by a
+// duplicate under the WithDedupe policy, by Swap, DrainAll or
+// UnmarshalBinary replacing everything, or by MoveItem moving them to
+// another scheduler. Items that expire or are dropped for lateness are
+// counted by TotalExpired and TotalDropped instead, and those dead-lettered
+// once taken for delivery have already been counted by TotalFired.
+func (s *Scheduler[T]) TotalCancelled() uint64 {
+	return s.metrics.cancelled.Load()
+}
+
+// TotalExpired returns how many Expiring items were dropped undelivered.
+func (s *Scheduler[T]) TotalExpired() uint64 {
+	return s.metrics.expired.Load()
+}
+
+// TotalDropped returns how many items were dropped undelivered for being
+// later than WithMaxLateness allows.
+func (s *Scheduler[T]) TotalDropped() uint64 {
+	return s.metrics.dropped.Load()
+}
+
+// CurrentDepth returns the number of items currently scheduled, like Len but
+// without taking the lock.
+func (s *Scheduler[T]) CurrentDepth() int64 {
+	return s.metrics.depth.Load()
+}
@@ -0,0 +1,76 @@
+package schedule
+
+import "sync/atomic"
+
+// schedulerCount hands out a unique lockOrder to every scheduler so that
+// operations locking two of them always do so in the same order.
+var schedulerCount atomic.Uint64
+
+// MoveItem moves the item with the given id from one scheduler to another,
+// keeping the due time it was scheduled with, and reports whether it was
+// moved. The item is added to to just as AddReminder would add it, so it can
+// be refused, e.g. as a duplicate or because to is at capacity, in which case
+// it stays where it was. It is reported as cancelled by from and added by to.
+// Both schedulers are locked for the duration of the move.
+func MoveItem[T Schedulable](from, to *Scheduler[T], id string) bool {
+	if from == to {
+		from.mutex.Lock()
+		defer from.unlock()
+		return from.find(id) != nil
+	}
+
+	first, second := from, to
+	if second.lockOrder < first.lockOrder {
+		first, second = second, first
+	}
+	first.mutex.Lock()
+	defer first.unlock()
+	second.mutex.Lock()
+	defer second.unlock()
+
+	from.update()
+	to.update()
+
+	entity := from.take(id)
+	if entity == nil {
+		return false
+	}
+
+	// to gets its own copy of the entry, since adding it gives it a new seq
+	// and may snap its due time, and from still needs the original to put
+	// back on refusal or to find its spans and in-flight entry by
+	moved := *entity
+
+	// Check first so that a refusal can't also dead-letter the item in to
+	if err := to.check(&moved); err != nil {
+		from.place(entity)
+		return false
+	}
+	if idx, _ := to.tryAdd(&moved); idx == Rejected {
+		from.place(entity)
+		return false
+	}
+
+	from.cancel(entity)
+	from.signalIfEmpty()
+	return true
+}
+
+// take removes and returns the first entry with the given id, or nil if there
+// isn't one.
+func (s *Scheduler[T]) take(id string) *entry[T] {
+	var taken *entry[T]
+	for _, bucket := range s.stores() {
+		bucket.removeWhere(func(e *entry[T]) bool {
+			if taken == nil && e.id == id {
+				taken = e
+				return true
+			}
+			return false
+		})
+		if taken != nil {
+			return taken
+		}
+	}
+	return nil
+}
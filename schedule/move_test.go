@@ -0,0 +1,178 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMoveItem(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	a := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+	b := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	a.AddReminder(testItem{id: "x", due: start.Add(2500 * time.Millisecond)})
+
+	if !MoveItem(a, b, "x") {
+		t.Fatal("MoveItem(a, b, x) = false, want true")
+	}
+	if MoveItem(a, b, "x") {
+		t.Error("MoveItem(a, b, x) = true once x had already moved")
+	}
+	if a.Len() != 0 || b.Len() != 1 {
+		t.Errorf("Len() = %d and %d after the move, want 0 and 1", a.Len(), b.Len())
+	}
+	if d, _ := b.TimeUntil("x"); d != 2500*time.Millisecond {
+		t.Errorf("moved item is due in %s, want 2.5s", d)
+	}
+}
+
+func TestMoveItemOppositeDirections(t *testing.T) {
+	a := NewScheduler[testItem](context.Background(), time.Second, 4)
+	b := NewScheduler[testItem](context.Background(), time.Second, 4)
+
+	for i := 0; i < 100; i++ {
+		a.AddReminder(testItem{id: "a", due: time.Now().Add(time.Second)})
+		b.AddReminder(testItem{id: "b", due: time.Now().Add(time.Second)})
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for MoveItem(a, b, "a") {
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for MoveItem(b, a, "b") {
+		}
+	}()
+	wg.Wait()
+
+	if a.Len()+b.Len() != 200 {
+		t.Errorf("Len() = %d and %d, want 200 between them", a.Len(), b.Len())
+	}
+}
+
+func TestMoveItemRefused(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	a := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+	b := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithDedupe[testItem](DedupeReject),
+		WithCapacity[testItem](1),
+	)
+
+	a.AddReminder(testItem{id: "x", due: start.Add(2500 * time.Millisecond)})
+	a.AddReminder(testItem{id: "y", due: start.Add(1500 * time.Millisecond)})
+	b.AddReminder(testItem{id: "x", due: start.Add(500 * time.Millisecond)})
+
+	for _, id := range []string{"x", "y"} {
+		if MoveItem(a, b, id) {
+			t.Errorf("MoveItem(a, b, %s) = true, want it refused by b", id)
+		}
+	}
+	if a.Len() != 2 || b.Len() != 1 {
+		t.Errorf("Len() = %d and %d after the refused moves, want 2 and 1", a.Len(), b.Len())
+	}
+	if d, _ := a.TimeUntil("x"); d != 2500*time.Millisecond {
+		t.Errorf("refused item is due in %s, want 2.5s", d)
+	}
+	if a.TotalCancelled() != 0 {
+		t.Errorf("TotalCancelled() = %d after the refused moves, want 0", a.TotalCancelled())
+	}
+}
+
+func TestMoveItemEvents(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	a := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+	b := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	a.AddReminder(testItem{id: "x", due: start.Add(2500 * time.Millisecond)})
+	removed, added := a.Events(), b.Events()
+
+	if !MoveItem(a, b, "x") {
+		t.Fatal("MoveItem(a, b, x) = false, want true")
+	}
+	if e := <-removed; e.Kind != Removed || e.Item.id != "x" {
+		t.Errorf("a emitted %v, want x removed", e)
+	}
+	if e := <-added; e.Kind != Added || e.Item.id != "x" {
+		t.Errorf("b emitted %v, want x added", e)
+	}
+	if a.TotalCancelled() != 1 || b.TotalScheduled() != 1 {
+		t.Errorf("TotalCancelled() = %d and TotalScheduled() = %d, want 1 and 1", a.TotalCancelled(), b.TotalScheduled())
+	}
+}
+
+func TestMoveItemSameScheduler(t *testing.T) {
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithDedupeKey(func(item testItem) string { return "key-" + item.id }),
+	)
+	s.AddReminder(testItem{id: "x", due: time.Now().Add(time.Second)})
+
+	if !MoveItem(s, s, "x") {
+		t.Error("MoveItem(s, s, x) = false, want true")
+	}
+	if MoveItem(s, s, "key-x") {
+		t.Error("MoveItem(s, s, key-x) = true, want it to look up Ids rather than keys")
+	}
+}
+
+func TestMoveItemBookkeeping(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	a := NewScheduler[spanningItem](context.Background(), time.Second, 4,
+		WithClock[spanningItem](clock),
+		WithAckTimeout[spanningItem](time.Hour),
+	)
+	b := NewScheduler[spanningItem](context.Background(), time.Second, 4, WithClock[spanningItem](clock))
+
+	// x is added to b with the seq d has in a
+	a.AddReminder(spanningItem{testItem{id: "d", due: start.Add(2500 * time.Millisecond)}, start.Add(3500 * time.Millisecond)})
+	a.AddReminder(spanningItem{testItem{id: "x", due: start.Add(500 * time.Millisecond)}, start.Add(3 * time.Second)})
+	clock.Advance(time.Second)
+	if peeked, _ := a.DuePeek(); len(peeked) != 1 {
+		t.Fatalf("DuePeek() = %v, want x in flight", peeked)
+	}
+
+	if !MoveItem(a, b, "x") {
+		t.Fatal("MoveItem(a, b, x) = false, want true")
+	}
+	at := start.Add(2700 * time.Millisecond)
+	if got := activeIds(a.ActiveAt(at)); got != "[d]" {
+		t.Errorf("a.ActiveAt() = %s after the move, want [d]", got)
+	}
+	if got := activeIds(b.ActiveAt(at)); got != "[x]" {
+		t.Errorf("b.ActiveAt() = %s after the move, want [x]", got)
+	}
+	if n := a.InFlight(); n != 0 {
+		t.Errorf("a.InFlight() = %d after moving x away, want 0", n)
+	}
+}
+
+func TestMoveItemRefusedKeepsDue(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	a := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+	b := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithDedupe[testItem](DedupeKeepEarliest),
+		WithDueGrid[testItem](time.Second),
+	)
+
+	a.AddReminder(testItem{id: "x", due: start.Add(1300 * time.Millisecond)})
+	b.AddReminder(testItem{id: "x", due: start.Add(500 * time.Millisecond)})
+
+	if MoveItem(a, b, "x") {
+		t.Fatal("MoveItem(a, b, x) = true, want it discarded by b as the later duplicate")
+	}
+	if d, _ := a.TimeUntil("x"); d != 1300*time.Millisecond {
+		t.Errorf("refused item is due in %s, want 1.3s", d)
+	}
+}
@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+)
+
+// NextN returns the n soonest-due items, without removing them, in due-time
+// order with ties broken by Id. It returns fewer if fewer are scheduled. Only
+// as many buckets are scanned as it takes to be sure of the n soonest, and
+// those are kept in a heap of size n, so small n stay cheap however much is
+// scheduled.
+func (s *Scheduler[T]) NextN(n int) []T {
+	if n <= 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	h := make(latestHeap[T], 0, n)
+	for i, bucket := range s.stores() {
+		if len(h) == n && s.earliestIn(i).After(h[0].due) {
+			// Nothing from here on can be sooner than what we have
+			break
+		}
+		for _, entity := range bucket.elements {
+			if len(h) < n {
+				heap.Push(&h, entity)
+			} else if entryBefore(entity, h[0]) {
+				h[0] = entity
+				heap.Fix(&h, 0)
+			}
+		}
+	}
+
+	sort.Slice(h, func(i, j int) bool { return entryBefore(h[i], h[j]) })
+	return items(h)
+}
+
+// earliestIn returns the earliest due time an item in stores()[i] can have.
+func (s *Scheduler[T]) earliestIn(i int) time.Time {
+	switch {
+	case i == 0:
+		// Overdue items are clamped into the head
+		return time.Time{}
+	case i < len(s.buckets):
+		return s.buckets[i].startTime
+	default:
+		// The overflow only holds items beyond the last bucket
+		return s.buckets[len(s.buckets)-1].endTime
+	}
+}
+
+// entryBefore orders entries by due time, then Id, then the order they were
+// added in.
+func entryBefore[T Schedulable](a, b *entry[T]) bool {
+	if a.due.Equal(b.due) {
+		if a.id == b.id {
+			return a.seq < b.seq
+		}
+		return a.id < b.id
+	}
+	return a.due.Before(b.due)
+}
+
+// latestHeap is a max-heap of entries, keeping the latest on top so it can be
+// evicted when something sooner turns up.
+type latestHeap[T Schedulable] []*entry[T]
+
+func (h latestHeap[T]) Len() int {
+	return len(h)
+}
+
+func (h latestHeap[T]) Less(i, j int) bool {
+	return entryBefore(h[j], h[i])
+}
+
+func (h latestHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *latestHeap[T]) Push(x any) {
+	*h = append(*h, x.(*entry[T]))
+}
+
+func (h *latestHeap[T]) Pop() any {
+	old := *h
+	entity := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entity
+}
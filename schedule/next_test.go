@@ -0,0 +1,53 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNextN(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	for _, overflow := range []bool{false, true} {
+		opts := []Option[testItem]{WithClock[testItem](clock)}
+		if overflow {
+			opts = append(opts, WithOverflow[testItem]())
+		}
+		s := NewScheduler[testItem](context.Background(), time.Second, 4, opts...)
+
+		rng := rand.New(rand.NewSource(1))
+		for i := 0; i < 200; i++ {
+			offset := time.Duration(rng.Int63n(int64(10*time.Second))) - 2*time.Second
+			s.AddReminder(testItem{id: fmt.Sprintf("%03d", i), due: start.Add(offset)})
+		}
+		s.AddReminder(testItem{id: "tie", due: start.Add(time.Second)})
+		s.AddReminder(testItem{id: "another tie", due: start.Add(time.Second)})
+
+		all := s.NextN(1000)
+		if len(all) != 202 {
+			t.Fatalf("overflow=%v: NextN(1000) returned %d items, want all 202", overflow, len(all))
+		}
+		for i := 1; i < len(all); i++ {
+			if dueBefore(all[i], all[i-1]) {
+				t.Fatalf("overflow=%v: NextN out of order at %d: %v after %v", overflow, i, all[i], all[i-1])
+			}
+		}
+
+		for _, n := range []int{1, 5, 50, 150} {
+			if got := s.NextN(n); !reflect.DeepEqual(got, all[:n]) {
+				t.Errorf("overflow=%v: NextN(%d) = %v, want %v", overflow, n, got, all[:n])
+			}
+		}
+		if got := s.NextN(0); got != nil {
+			t.Errorf("overflow=%v: NextN(0) = %v, want nil", overflow, got)
+		}
+		if n := s.Len(); n != 202 {
+			t.Errorf("overflow=%v: Len() = %d after NextN, want 202", overflow, n)
+		}
+	}
+}
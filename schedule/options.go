@@ -0,0 +1,134 @@
+package schedule
+
+import "time"
+
+// Option configures a Scheduler at construction time.
+type Option[T Schedulable] func(*Scheduler[T])
+
+// WithClock makes the scheduler read the current time from clock instead of
+// the system clock.
+func WithClock[T Schedulable](clock Clock) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.clock = clock
+	}
+}
+
+// WithOnExpire registers fn to be called with every Expiring item that is
+// dropped because it was not delivered before it expired.
+func WithOnExpire[T Schedulable](fn func(T)) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.onExpire = fn
+	}
+}
+
+// WithDeferSpread makes DeferOverdue spread the items it moves evenly over
+// window, in their original due order, rather than giving them all the same
+// due time.
+func WithDeferSpread[T Schedulable](window time.Duration) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.deferSpread = window
+	}
+}
+
+// WithOrder sets the order Due hands out items that are due at the same time.
+// The default is SoonestFirst.
+func WithOrder[T Schedulable](order Order) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.order = order
+	}
+}
+
+// WithStrictFIFO makes Due hand out due items strictly in the order they were
+// added, treating the due time only as a lower bound. Every due item is held in
+// the head bucket, so the ordering is global rather than per bucket. It is
+// shorthand for WithOrder(InsertionOrder).
+func WithStrictFIFO[T Schedulable]() Option[T] {
+	return WithOrder[T](InsertionOrder)
+}
+
+// DedupePolicy decides what happens when an item is added with the same Id()
+// as one that is already scheduled.
+type DedupePolicy int
+
+const (
+	// DedupeAllow schedules both items.
+	DedupeAllow DedupePolicy = iota
+	// DedupeReplace cancels the existing item in favour of the new one.
+	DedupeReplace
+	// DedupeReject refuses the new item with ErrDuplicateId.
+	DedupeReject
+	// DedupeKeepEarliest keeps whichever item is due sooner, cancelling the
+	// existing one or quietly discarding the new one.
+	DedupeKeepEarliest
+	// DedupeKeepLatest keeps whichever item is due later, cancelling the
+	// existing one or quietly discarding the new one.
+	DedupeKeepLatest
+)
+
+// WithDedupe sets the policy for items added with an Id() that is already
+// scheduled. The default is DedupeAllow.
+func WithDedupe[T Schedulable](policy DedupePolicy) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.dedupe = policy
+	}
+}
+
+// WithOnBucketActive registers fn to be called, outside the scheduler's lock,
+// each time a new bucket becomes the head with that bucket's window and the
+// number of items it holds.
+func WithOnBucketActive[T Schedulable](fn func(start, end time.Time, size int)) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.onBucketActive = fn
+	}
+}
+
+// WithOnTick registers fn to be called, outside the scheduler's lock, once
+// every time the buckets rotate, with the time and the number of items then
+// scheduled, whether or not anything is due. The buckets rotate as the
+// scheduler is used or by the loop started by Start.
+func WithOnTick[T Schedulable](fn func(now time.Time, depth int)) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.onTick = fn
+	}
+}
+
+// WithDedupeKey makes the dedupe policy and Has identify items by key(item)
+// rather than Id(). Cancel, TimeUntil and the other id based lookups still use
+// Id(), so two items can share a dedupe key while having different ids, or
+// the reverse.
+func WithDedupeKey[T Schedulable](key func(T) string) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.dedupeKey = key
+	}
+}
+
+// CatchUpMode decides how a recurring item that has fallen more than an
+// interval behind, e.g. after a pause, is scheduled again once it fires.
+type CatchUpMode int
+
+const (
+	// FireAll schedules the next occurrence one interval after the last, so
+	// every missed occurrence still fires, one after another.
+	FireAll CatchUpMode = iota
+	// Skip schedules the next occurrence at the first whole interval after
+	// now, so missed occurrences are skipped and the item fires once.
+	Skip
+)
+
+// WithCatchUpMode sets how late recurring items catch up. The default is
+// FireAll.
+func WithCatchUpMode[T Schedulable](mode CatchUpMode) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.catchUp = mode
+	}
+}
+
+// WithMaxFuture rejects items due more than d after now with ErrTooFar, as a
+// guard against bad input. It is independent of the horizon: items due within
+// d but beyond the horizon are still accepted and clamped or overflow as
+// usual. The default is no limit.
+func WithMaxFuture[T Schedulable](d time.Duration) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.maxFuture = d
+	}
+}
@@ -0,0 +1,33 @@
+package schedule
+
+import "sort"
+
+// Order controls the order in which items that are due at the same time are
+// handed out.
+type Order int
+
+const (
+	// SoonestFirst delivers the item with the earliest due time first, and
+	// items due at the same time in order of Id.
+	SoonestFirst Order = iota
+	// LatestFirst delivers the item with the latest due time first, for
+	// workloads where newer items supersede older ones.
+	LatestFirst
+	// InsertionOrder delivers items in the order they were added.
+	InsertionOrder
+)
+
+// sortForDelivery orders entries taken from the head bucket, which are
+// already soonest first, according to order.
+func sortForDelivery[T Schedulable](order Order, entries []*entry[T]) {
+	switch order {
+	case LatestFirst:
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].due.After(entries[j].due)
+		})
+	case InsertionOrder:
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].seq < entries[j].seq
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+)
+
+// WithOverflow keeps items due beyond the last bucket in a separate store,
+// sorted by due time, instead of clamping them into the last bucket. As the
+// horizon moves forward they are migrated into the bucket they belong in.
+func WithOverflow[T Schedulable]() Option[T] {
+	return func(s *Scheduler[T]) {
+		s.overflow = NewTimespanBucket[*entry[T]](time.Time{}, time.Time{})
+	}
+}
+
+// OverflowLen returns the number of items waiting beyond the horizon in the
+// overflow store. It is always zero without WithOverflow.
+func (s *Scheduler[T]) OverflowLen() int {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	if s.overflow == nil {
+		return 0
+	}
+	return s.overflow.Size()
+}
+
+// migrateOverflow moves overflow items that are now within the horizon into
+// their buckets.
+func (s *Scheduler[T]) migrateOverflow() {
+	if s.overflow == nil {
+		return
+	}
+
+	horizon := s.buckets[len(s.buckets)-1].endTime
+	overflow := s.overflow.elements
+	n := sort.Search(len(overflow), func(i int) bool {
+		return !overflow[i].due.Before(horizon)
+	})
+
+	migrating := overflow[:n:n]
+	s.overflow.elements = overflow[n:]
+	for _, entity := range migrating {
+		s.place(entity)
+	}
+}
+
+func (s *Scheduler[T]) clearOverflow() {
+	if s.overflow != nil {
+		s.overflow.elements = make([]*entry[T], 0)
+	}
+}
@@ -0,0 +1,52 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestOverflow(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithOverflow[testItem](),
+	)
+
+	s.AddReminder(testItem{id: "later", due: start.Add(9500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "soon", due: start.Add(6500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "now", due: start.Add(500 * time.Millisecond)})
+
+	if n := s.OverflowLen(); n != 2 {
+		t.Fatalf("OverflowLen() = %d, want 2", n)
+	}
+	if n := s.Len(); n != 3 {
+		t.Fatalf("Len() = %d, want 3", n)
+	}
+
+	clock.Advance(3 * time.Second)
+	if n := s.OverflowLen(); n != 1 {
+		t.Errorf("OverflowLen() = %d after the horizon moved past one item, want 1", n)
+	}
+	if occupancy := s.Occupancy(); occupancy[3] != 1 {
+		t.Errorf("occupancy = %v, want the migrated item in the last bucket", occupancy)
+	}
+	if err := s.Check(); err != nil {
+		t.Errorf("Check() = %v", err)
+	}
+
+	var fired []string
+	for i := 0; i < 10; i++ {
+		for _, item := range s.Due() {
+			fired = append(fired, item.id)
+			if clock.Now().Before(item.due) {
+				t.Errorf("%s fired at %s, before it was due at %s", item.id, clock.Now(), item.due)
+			}
+		}
+		clock.Advance(time.Second)
+	}
+	if len(fired) != 3 {
+		t.Errorf("fired %v, want all 3 items", fired)
+	}
+}
@@ -0,0 +1,63 @@
+package schedule
+
+// Pause freezes the scheduler: buckets stop rotating and nothing is handed out
+// by Due, its variants or the Start loop. Items can still be added and
+// cancelled while paused.
+func (s *Scheduler[T]) Pause() {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	s.paused = true
+}
+
+// Resume undoes Pause. Every bucket that elapsed while paused is rotated out
+// at once, so everything that came due during the pause is delivered by the
+// next Due call (or Start loop sweep) as overdue.
+func (s *Scheduler[T]) Resume() {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	s.paused = false
+	s.update()
+}
+
+// Paused reports whether the scheduler is paused.
+func (s *Scheduler[T]) Paused() bool {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	return s.paused
+}
+
+// Mute stops Due, its variants and the Start loop from handing anything out
+// while leaving the buckets rotating, so items keep coming due and accumulate
+// as overdue, e.g. during a maintenance window. Items can still be added and
+// cancelled while muted.
+func (s *Scheduler[T]) Mute() {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	s.muted = true
+}
+
+// Unmute undoes Mute. Everything that came due while muted is delivered by
+// the next Due call, or by the Start loop subject to WithOverdueRelease.
+func (s *Scheduler[T]) Unmute() {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	s.muted = false
+}
+
+// Muted reports whether the scheduler is muted.
+func (s *Scheduler[T]) Muted() bool {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	return s.muted
+}
+
+// delivering reports whether items may be handed out.
+func (s *Scheduler[T]) delivering() bool {
+	return !s.paused && !s.muted
+}
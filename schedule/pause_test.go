@@ -0,0 +1,72 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPauseResume(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(2500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "c", due: start.Add(time.Minute)})
+
+	s.Pause()
+	clock.Advance(10 * time.Second)
+
+	if due := s.Due(); len(due) != 0 {
+		t.Errorf("Due() returned %d items while paused", len(due))
+	}
+	if windows := s.Windows(); !windows[0][0].Equal(start) {
+		t.Errorf("buckets rotated while paused, head starts at %s", windows[0][0])
+	}
+
+	s.Resume()
+	due := s.Due()
+	if len(due) != 2 || due[0].id != "a" || due[1].id != "b" {
+		t.Errorf("Due() after Resume = %v, want [a b]", due)
+	}
+	if err := s.Check(); err != nil {
+		t.Errorf("Check() after Resume = %v", err)
+	}
+}
+
+func TestMuteUnmute(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(2500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "c", due: start.Add(time.Minute)})
+
+	s.Mute()
+	clock.Advance(10 * time.Second)
+	s.AddReminder(testItem{id: "d", due: start.Add(9 * time.Second)})
+
+	if due := s.Due(); len(due) != 0 {
+		t.Errorf("Due() returned %d items while muted", len(due))
+	}
+	if due := s.DrainDue(); len(due) != 0 {
+		t.Errorf("DrainDue() returned %d items while muted", len(due))
+	}
+	if windows := s.Windows(); !windows[0][0].Equal(start.Add(10 * time.Second)) {
+		t.Errorf("head starts at %s while muted, want the buckets still rotating", windows[0][0].Sub(start))
+	}
+	if n := s.OverdueCount(); n != 3 {
+		t.Errorf("OverdueCount() = %d while muted, want 3", n)
+	}
+
+	s.Unmute()
+	due := s.Due()
+	if len(due) != 3 || due[0].id != "a" || due[1].id != "b" || due[2].id != "d" {
+		t.Errorf("Due() after Unmute = %v, want [a b d]", due)
+	}
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d after Unmute, want 1", s.Len())
+	}
+}
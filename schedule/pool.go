@@ -0,0 +1,63 @@
+package schedule
+
+// StartN runs the loop started by Start, but with workers goroutines taking
+// due items off it and passing them to the WithRouter or WithOnDue handler,
+// so one slow handler doesn't hold up everything behind it. Items without a
+// handler are passed to WithDeadLetter as Unrouted. Stop ends the loop; each
+// worker finishes the item it is handling and then exits.
+//
+// WithMaxConcurrent caps how many handlers run at once independently of
+// workers. With fewer slots than workers, the extra workers each hold an item
+// taken from the scheduler while they wait for a slot; with more, the limit
+// has no effect. StartN does nothing if the loop is already running.
+func (s *Scheduler[T]) StartN(workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var slots chan struct{}
+	if s.maxConcurrent > 0 {
+		slots = make(chan struct{}, s.maxConcurrent)
+	}
+
+	work := make(chan *entry[T])
+	if _, started := s.start(nil, work); !started {
+		return
+	}
+	for i := 0; i < workers; i++ {
+		go s.work(work, slots)
+	}
+}
+
+// WithMaxConcurrent limits StartN to n handlers running at once, for handlers
+// that call something which can only take so much. The default is one per
+// worker.
+func WithMaxConcurrent[T Schedulable](n int) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.maxConcurrent = n
+	}
+}
+
+// work handles items from the loop until it stops, taking one of slots, if
+// there are any, for each.
+func (s *Scheduler[T]) work(work <-chan *entry[T], slots chan struct{}) {
+	for entity := range work {
+		if slots != nil {
+			slots <- struct{}{}
+		}
+		if !s.handle(entity) {
+			s.unhandled(entity)
+		}
+		if slots != nil {
+			<-slots
+		}
+	}
+}
+
+func (s *Scheduler[T]) unhandled(entity *entry[T]) {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	s.logger.Log(LevelWarn, "no handler for item", "id", entity.id)
+	s.lost(entity, Unrouted)
+}
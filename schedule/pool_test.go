@@ -0,0 +1,62 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStartNMaxConcurrent(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	var wg sync.WaitGroup
+	var running, peak atomic.Int32
+	handler := func(testItem) error {
+		defer wg.Done()
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithOnDue(handler),
+		WithMaxConcurrent[testItem](3),
+	)
+	for i := 0; i < 20; i++ {
+		s.AddReminder(testItem{id: fmt.Sprintf("%02d", i), due: start.Add(-time.Second)})
+	}
+
+	wg.Add(20)
+	s.StartN(8)
+	defer s.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the handlers")
+	}
+
+	if p := peak.Load(); p > 3 {
+		t.Errorf("%d handlers ran at once, want at most 3", p)
+	}
+	if n := s.Len(); n != 0 {
+		t.Errorf("Len() = %d, want 0", n)
+	}
+}
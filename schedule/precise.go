@@ -0,0 +1,46 @@
+package schedule
+
+import "time"
+
+// WithPreciseTimers makes the loop started by Start set its timer for the
+// soonest item's due time whenever that comes before its next regular check,
+// and re-arm it when something sooner is added, so items are delivered as
+// soon as they are due however wide the buckets are. Buckets still rotate at
+// least every blockSize, or WithTickInterval. The cost is more timer resets
+// and lock acquisitions when items are spread thinly over time. It has no
+// effect with WithFlushAlignment.
+func WithPreciseTimers[T Schedulable]() Option[T] {
+	return func(s *Scheduler[T]) {
+		s.precise = true
+		s.wake = make(chan struct{}, 1)
+	}
+}
+
+// untilNextDue returns how long the loop should wait for the soonest item,
+// at most poll, and remembers when that is so nudge knows what's sooner.
+func (s *Scheduler[T]) untilNextDue(poll time.Duration) time.Duration {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	now := s.now()
+	wait := poll
+	// Anything already due has just had its chance; waiting for it again
+	// would spin while it's held, paused or rate limited
+	if next, ok := s.nextDue(); ok && next.After(now) && next.Sub(now) < wait {
+		wait = next.Sub(now)
+	}
+	s.armed = now.Add(wait)
+	return wait
+}
+
+// nudge wakes the loop if an item due at due should be delivered before its
+// timer goes off. It must be called with the lock held.
+func (s *Scheduler[T]) nudge(due time.Time) {
+	if s.wake == nil || !due.Before(s.armed) {
+		return
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,39 @@
+package schedule
+
+import "time"
+
+// ClockRegression decides what the scheduler does when its clock reports a
+// time earlier than one it has already seen, e.g. after an NTP step.
+type ClockRegression int
+
+const (
+	// Ignore leaves the buckets where they are; nothing rotates until the
+	// clock catches up with them again.
+	Ignore ClockRegression = iota
+	// Rebuild lays the buckets out afresh starting at the new now and
+	// re-bins every item into them.
+	Rebuild
+)
+
+// WithClockRegression sets how the scheduler handles its clock going
+// backwards. The default is Ignore.
+func WithClockRegression[T Schedulable](policy ClockRegression) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.regression = policy
+	}
+}
+
+// observe records now as the latest time seen and applies the clock
+// regression policy if it is earlier than the last one.
+func (s *Scheduler[T]) observe(now time.Time) {
+	last := s.lastNow
+	s.lastNow = now
+	if last.IsZero() || !now.Before(last) {
+		return
+	}
+
+	s.logger.Log(LevelWarn, "clock went backwards", "now", now, "last", last, "by", last.Sub(now))
+	if s.regression == Rebuild && len(s.buckets) > 0 {
+		s.rebin(now, 0)
+	}
+}
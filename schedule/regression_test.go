@@ -0,0 +1,52 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClockRegression(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+
+	for _, policy := range []ClockRegression{Ignore, Rebuild} {
+		clock := NewFakeClock(start)
+		s := NewScheduler[testItem](context.Background(), time.Second, 4,
+			WithClock[testItem](clock),
+			WithClockRegression[testItem](policy),
+		)
+
+		s.AddReminder(testItem{id: "a", due: start.Add(1500 * time.Millisecond)})
+		s.AddReminder(testItem{id: "b", due: start.Add(3500 * time.Millisecond)})
+
+		clock.Advance(2 * time.Second)
+		if due := s.Due(); len(due) != 1 {
+			t.Fatalf("policy %d: Due() = %v, want [a]", policy, due)
+		}
+
+		clock.Set(start.Add(-time.Minute))
+		if due := s.Due(); len(due) != 0 {
+			t.Errorf("policy %d: Due() = %v after going backwards, want nothing", policy, due)
+		}
+		if err := s.Check(); err != nil {
+			t.Errorf("policy %d: Check() = %v", policy, err)
+		}
+
+		head := s.Windows()[0][0]
+		switch policy {
+		case Ignore:
+			if !head.Equal(start.Add(2 * time.Second)) {
+				t.Errorf("Ignore: head starts at %s, want the buckets left alone", head.Sub(start))
+			}
+		case Rebuild:
+			if !head.Equal(clock.Now()) {
+				t.Errorf("Rebuild: head starts at %s, want the new now", head.Sub(start))
+			}
+		}
+
+		clock.Set(start.Add(4 * time.Second))
+		if due := s.Due(); len(due) != 1 || due[0].id != "b" {
+			t.Errorf("policy %d: Due() = %v once the clock caught up, want [b]", policy, due)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+// Reminder is a ready-made Schedulable carrying an arbitrary payload.
+type Reminder struct {
+	ID   string
+	Due  time.Time
+	Data any
+	// Weekly, if set, makes the reminder recur at the same time of day on
+	// the same day each week.
+	Weekly *WeeklyAt
+}
+
+func (r Reminder) DueTime() time.Time {
+	return r.Due
+}
+
+func (r Reminder) Id() string {
+	return r.ID
+}
+
+// Interval returns a week for weekly reminders and zero otherwise. The actual
+// next occurrence of a weekly reminder follows the wall clock, so it can be an
+// hour either way of this across a daylight saving change.
+func (r Reminder) Interval() time.Duration {
+	if r.Weekly != nil {
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// WeeklyRule returns the reminder's weekly schedule, or nil if it has none.
+func (r Reminder) WeeklyRule() *WeeklyAt {
+	return r.Weekly
+}
+
+// WeeklyRecurring items that return a non-nil WeeklyRule recur at its time
+// of day on its weekday, following the wall clock, instead of a fixed
+// Interval() after each time they fire. They must still be Recurring.
+type WeeklyRecurring interface {
+	WeeklyRule() *WeeklyAt
+}
+
+// WeeklyAt is a time of day on a day of the week.
+type WeeklyAt struct {
+	Weekday time.Weekday
+	// At is the time of day as an offset from midnight on the wall clock.
+	At time.Duration
+}
+
+// Next returns the first time strictly after t that falls on w's weekday and
+// time of day in t's location.
+func (w WeeklyAt) Next(t time.Time) time.Time {
+	y, m, d := t.Date()
+	days := (int(w.Weekday) - int(t.Weekday()) + 7) % 7
+
+	next := w.on(y, m, d+days, t.Location())
+	if !next.After(t) {
+		next = w.on(y, m, d+days+7, t.Location())
+	}
+	return next
+}
+
+// on returns w's time of day on the given date. Building it from the civil
+// fields rather than adding At to midnight keeps it on the wall clock time
+// across daylight saving changes.
+func (w WeeklyAt) on(y int, m time.Month, d int, loc *time.Location) time.Time {
+	hour := int(w.At / time.Hour)
+	min := int(w.At % time.Hour / time.Minute)
+	sec := int(w.At % time.Minute / time.Second)
+	nsec := int(w.At % time.Second)
+	return time.Date(y, m, d, hour, min, sec, nsec, loc)
+}
+
+// TimeScheduler is a Scheduler of Reminders for the common case where items
+// are just an id, a due time and some data.
+type TimeScheduler struct {
+	*Scheduler[Reminder]
+}
+
+func NewTimeScheduler(ctx context.Context, blockSize time.Duration, numBlocks int, opts ...Option[Reminder]) *TimeScheduler {
+	return &TimeScheduler{
+		Scheduler: NewScheduler[Reminder](ctx, blockSize, numBlocks, opts...),
+	}
+}
+
+// Schedule adds a reminder with the given id that is due at due.
+func (t *TimeScheduler) Schedule(id string, due time.Time, data any) error {
+	return t.AddReminder(Reminder{ID: id, Due: due, Data: data})
+}
+
+// ScheduleWeekly adds a reminder with the given id that is due at the next
+// time, according to the scheduler's clock and in its location, that it is at
+// on weekday. It recurs at that time every week after.
+func (t *TimeScheduler) ScheduleWeekly(id string, weekday time.Weekday, at time.Duration, data any) error {
+	weekly := &WeeklyAt{Weekday: weekday, At: at}
+	return t.AddReminder(Reminder{ID: id, Due: weekly.Next(t.clock.Now()), Data: data, Weekly: weekly})
+}
@@ -0,0 +1,92 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimeScheduler(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewTimeScheduler(context.Background(), time.Second, 10, WithClock[Reminder](clock))
+
+	s.Schedule("birthday", start.Add(2*time.Second), "cake")
+	s.Schedule("dentist", start.Add(3*time.Second), nil)
+
+	if !s.Cancel("dentist") {
+		t.Error("Cancel(dentist) = false, want true")
+	}
+	if s.Cancel("dentist") {
+		t.Error("Cancel(dentist) = true for an already cancelled reminder")
+	}
+
+	clock.Advance(5 * time.Second)
+	due := s.Due()
+	if len(due) != 1 || due[0].ID != "birthday" || due[0].Data != "cake" {
+		t.Errorf("Due() = %v, want the birthday reminder", due)
+	}
+}
+
+func TestWeeklyAtNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+
+	// Thursday 3 November 2022; clocks went back on Sunday 6 November
+	thursday := time.Date(2022, 11, 3, 11, 0, 0, 0, ny)
+
+	for _, tc := range []struct {
+		name string
+		w    WeeklyAt
+		from time.Time
+		want time.Time
+	}{
+		{"later this week", WeeklyAt{time.Friday, 9*time.Hour + 30*time.Minute}, thursday, time.Date(2022, 11, 4, 9, 30, 0, 0, ny)},
+		{"later today", WeeklyAt{time.Thursday, 17 * time.Hour}, thursday, time.Date(2022, 11, 3, 17, 0, 0, 0, ny)},
+		{"earlier today", WeeklyAt{time.Thursday, 9 * time.Hour}, thursday, time.Date(2022, 11, 10, 9, 0, 0, 0, ny)},
+		{"exactly now", WeeklyAt{time.Thursday, 11 * time.Hour}, thursday, time.Date(2022, 11, 10, 11, 0, 0, 0, ny)},
+		{"across the weekend", WeeklyAt{time.Monday, 8 * time.Hour}, thursday, time.Date(2022, 11, 7, 8, 0, 0, 0, ny)},
+		{"across the month", WeeklyAt{time.Tuesday, 0}, time.Date(2022, 11, 30, 1, 0, 0, 0, ny), time.Date(2022, 12, 6, 0, 0, 0, 0, ny)},
+		{"across the change", WeeklyAt{time.Friday, 9*time.Hour + 30*time.Minute}, time.Date(2022, 11, 4, 9, 30, 0, 0, ny), time.Date(2022, 11, 11, 9, 30, 0, 0, ny)},
+	} {
+		if got := tc.w.Next(tc.from); !got.Equal(tc.want) {
+			t.Errorf("%s: Next(%s) = %s, want %s", tc.name, tc.from, got, tc.want)
+		}
+	}
+}
+
+func TestScheduleWeekly(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+
+	start := time.Date(2022, 11, 3, 11, 0, 0, 0, ny)
+	clock := NewFakeClock(start)
+	s := NewTimeScheduler(context.Background(), time.Hour, 24, WithClock[Reminder](clock))
+
+	if err := s.ScheduleWeekly("standup", time.Friday, 9*time.Hour+30*time.Minute, "notes"); err != nil {
+		t.Fatalf("ScheduleWeekly() = %v", err)
+	}
+
+	first := time.Date(2022, 11, 4, 9, 30, 0, 0, ny)
+	if due, ok := s.NextDueTime(); !ok || !due.Equal(first) {
+		t.Fatalf("NextDue() = %s, want %s", due, first)
+	}
+
+	clock.Set(first)
+	if due := s.Due(); len(due) != 1 || due[0].Data != "notes" {
+		t.Fatalf("Due() = %v, want the standup", due)
+	}
+
+	// The clocks go back in between, so it is 169 hours until the next one
+	second := time.Date(2022, 11, 11, 9, 30, 0, 0, ny)
+	if due, ok := s.NextDueTime(); !ok || !due.Equal(second) {
+		t.Errorf("NextDue() after firing = %s, want %s", due, second)
+	}
+	if gap := second.Sub(first); gap != 169*time.Hour {
+		t.Errorf("gap = %s, want 169h", gap)
+	}
+}
@@ -0,0 +1,110 @@
+package schedule
+
+// WithRouter makes the loop started by Start deliver each due item to the
+// handler registered with Handle for its category, as given by route, instead
+// of sending it on the channel or to WithOnDue. Items whose category has no
+// handler are passed to WithDeadLetter as Unrouted.
+func WithRouter[T Schedulable](route func(T) string) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.router = route
+	}
+}
+
+// Handle registers fn as the handler for items in category, replacing any
+// handler already registered for it. It has no effect without WithRouter.
+// fn is called from the loop started by Start, outside the scheduler's lock.
+func (s *Scheduler[T]) Handle(category string, fn func(T)) {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	if s.handlers == nil {
+		s.handlers = make(map[string]func(T))
+	}
+	s.handlers[category] = fn
+}
+
+// route delivers entity to the handler for its category.
+func (s *Scheduler[T]) route(entity *entry[T]) {
+	category := s.router(entity.item)
+
+	s.mutex.Lock()
+	handler, ok := s.handlers[category]
+	if !ok {
+		s.logger.Log(LevelWarn, "no handler for item", "id", entity.id, "category", category)
+		s.lost(entity, Unrouted)
+	}
+	s.unlock()
+
+	if ok {
+		handler(entity.item)
+	}
+}
+
+// LenByCategory returns how many items are scheduled in each WithRouter
+// category, as Len does for the whole scheduler. Categories with nothing
+// scheduled are left out. It returns nil without WithRouter.
+func (s *Scheduler[T]) LenByCategory() map[string]int {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	if s.router == nil {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, bucket := range s.stores() {
+		for _, entity := range bucket.elements {
+			counts[s.router(entity.item)]++
+		}
+	}
+	return counts
+}
+
+// DueFair removes and returns everything that is due, like Due, but
+// interleaved across the WithRouter categories in proportion to weights
+// instead of strictly in due-time order, so a backlog in one category doesn't
+// starve the others. Each round takes up to weights[category] items from every
+// category, soonest first, starting with the category whose soonest item is
+// the most overdue. Categories missing from weights, or with a weight below
+// one, get one. Without WithRouter it is the same as Due.
+func (s *Scheduler[T]) DueFair(weights map[string]int) []T {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	due := s.takeDue(-1)
+	if s.router == nil {
+		return items(due)
+	}
+
+	// takeDue returns entries in delivery order, so each queue is too
+	queues := make(map[string][]*entry[T])
+	categories := make([]string, 0)
+	for _, entity := range due {
+		category := s.router(entity.item)
+		if _, ok := queues[category]; !ok {
+			categories = append(categories, category)
+		}
+		queues[category] = append(queues[category], entity)
+	}
+
+	fair := make([]T, 0, len(due))
+	for len(fair) < len(due) {
+		for _, category := range categories {
+			weight := weights[category]
+			if weight < 1 {
+				weight = 1
+			}
+
+			queue := queues[category]
+			if weight > len(queue) {
+				weight = len(queue)
+			}
+			for _, entity := range queue[:weight] {
+				fair = append(fair, entity.item)
+			}
+			queues[category] = queue[weight:]
+		}
+	}
+	return fair
+}
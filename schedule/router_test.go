@@ -0,0 +1,133 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouter(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	unrouted := make(chan string, 10)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithRouter(func(item testItem) string {
+			return strings.SplitN(item.id, ":", 2)[0]
+		}),
+		WithDeadLetter(func(item testItem, reason Reason) {
+			if reason == Unrouted {
+				unrouted <- item.id
+			}
+		}),
+	)
+
+	emails, pages := make(chan string, 10), make(chan string, 10)
+	s.Handle("email", func(item testItem) { emails <- item.id })
+	s.Handle("page", func(item testItem) { pages <- item.id })
+
+	s.AddReminder(testItem{id: "email:1", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "page:1", due: start.Add(600 * time.Millisecond)})
+	s.AddReminder(testItem{id: "sms:1", due: start.Add(700 * time.Millisecond)})
+
+	out := s.Start()
+	defer s.Stop()
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+
+	for _, c := range []struct {
+		ch   chan string
+		want string
+	}{{emails, "email:1"}, {pages, "page:1"}, {unrouted, "sms:1"}} {
+		select {
+		case id := <-c.ch:
+			if id != c.want {
+				t.Errorf("got %s, want %s", id, c.want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", c.want)
+		}
+	}
+
+	select {
+	case item := <-out:
+		t.Errorf("%s was sent on the channel, want it routed", item.id)
+	default:
+	}
+}
+
+func TestDueFair(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	category := func(item testItem) string { return item.id[:1] }
+
+	backlog := func() *Scheduler[testItem] {
+		s := NewScheduler[testItem](context.Background(), time.Second, 4,
+			WithClock[testItem](clock), WithRouter(category))
+		for i := 0; i < 100; i++ {
+			s.AddReminder(testItem{id: fmt.Sprintf("A%03d", i), due: start.Add(time.Duration(i-200) * time.Second)})
+		}
+		for i := 0; i < 10; i++ {
+			s.AddReminder(testItem{id: fmt.Sprintf("B%03d", i), due: start.Add(time.Duration(i-50) * time.Second)})
+		}
+		return s
+	}
+
+	order := func(due []testItem) string {
+		var b strings.Builder
+		for _, item := range due {
+			b.WriteString(category(item))
+		}
+		return b.String()
+	}
+
+	due := backlog().DueFair(map[string]int{"A": 1, "B": 1})
+	if len(due) != 110 {
+		t.Fatalf("DueFair() returned %d items, want 110", len(due))
+	}
+	if got, want := order(due), strings.Repeat("AB", 10)+strings.Repeat("A", 90); got != want {
+		t.Errorf("DueFair(A:1, B:1) order = %s, want %s", got, want)
+	}
+	for i := 1; i < len(due); i++ {
+		if category(due[i]) == category(due[i-1]) && due[i].due.Before(due[i-1].due) {
+			t.Fatalf("%s came after %s within its category", due[i].id, due[i-1].id)
+		}
+	}
+
+	due = backlog().DueFair(map[string]int{"B": 3})
+	if got, want := order(due)[:16], "ABBBABBBABBBABAA"; got != want {
+		t.Errorf("DueFair(B:3) order starts %s, want %s", got, want)
+	}
+}
+
+func TestLenByCategory(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithOverflow[testItem](),
+		WithRouter(func(item testItem) string {
+			return strings.SplitN(item.id, ":", 2)[0]
+		}),
+	)
+
+	s.AddReminder(testItem{id: "email:1", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "email:2", due: start.Add(time.Hour)})
+	s.AddReminder(testItem{id: "page:1", due: start.Add(2 * time.Second)})
+	s.AddReminder(testItem{id: "sms:1", due: start.Add(700 * time.Millisecond)})
+	s.Cancel("sms:1")
+
+	want := map[string]int{"email": 2, "page": 1}
+	if got := s.LenByCategory(); !reflect.DeepEqual(got, want) {
+		t.Errorf("LenByCategory() = %v, want %v", got, want)
+	}
+
+	unrouted := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+	if got := unrouted.LenByCategory(); got != nil {
+		t.Errorf("LenByCategory() without a router = %v, want nil", got)
+	}
+}
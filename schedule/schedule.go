@@ -3,7 +3,9 @@ package schedule
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,6 +14,23 @@ type Schedulable interface {
 	Id() string
 }
 
+// Recurring items are scheduled again Interval() after each time they fire.
+type Recurring interface {
+	Interval() time.Duration
+}
+
+// LimitedRecurring items stop recurring once they have fired MaxOccurrences()
+// times. A limit of zero or less means no limit.
+type LimitedRecurring interface {
+	Recurring
+	MaxOccurrences() int
+}
+
+// Expiring items are dropped instead of delivered once ExpiresAt has passed.
+type Expiring interface {
+	ExpiresAt() time.Time
+}
+
 type TimespanBucket[T Schedulable] struct {
 	startTime time.Time
 	endTime   time.Time
@@ -29,7 +48,7 @@ func NewTimespanBucket[T Schedulable](startTime time.Time, endTime time.Time) *T
 }
 
 func (t *TimespanBucket[Schedulable]) Contains(in time.Time) bool {
-	return t.startTime.Before(in) && t.endTime.After(in)
+	return !t.startTime.After(in) && t.endTime.After(in)
 }
 
 func (t *TimespanBucket[T]) AddEntity(entity T) {
@@ -38,8 +57,79 @@ func (t *TimespanBucket[T]) AddEntity(entity T) {
 	t.elements = append(t.elements, entity)
 }
 
+func (t *TimespanBucket[T]) reserve(capacity int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if cap(t.elements) < capacity {
+		elements := make([]T, len(t.elements), capacity)
+		copy(elements, t.elements)
+		t.elements = elements
+	}
+}
+
+// insertSorted adds entity after any elements due at or before it, keeping an
+// already sorted bucket sorted.
+func (t *TimespanBucket[T]) insertSorted(entity T) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	idx := sort.Search(len(t.elements), func(i int) bool {
+		return dueBefore(entity, t.elements[i])
+	})
+
+	var zero T
+	t.elements = append(t.elements, zero)
+	copy(t.elements[idx+1:], t.elements[idx:])
+	t.elements[idx] = entity
+}
+
+func (t *TimespanBucket[T]) sortByDue() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	sort.SliceStable(t.elements, func(i, j int) bool {
+		return dueBefore(t.elements[i], t.elements[j])
+	})
+}
+
+// dueBefore orders items by due time, breaking ties by Id so that items due
+// at the same instant always come out in the same order.
+func dueBefore[T Schedulable](a, b T) bool {
+	aDue, bDue := a.DueTime(), b.DueTime()
+	if aDue.Equal(bDue) {
+		return a.Id() < b.Id()
+	}
+	return aDue.Before(bDue)
+}
+
+func (t *TimespanBucket[T]) removeWhere(pred func(T) bool) []T {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	removed := make([]T, 0)
+	kept := t.elements[:0]
+	for _, entity := range t.elements {
+		if pred(entity) {
+			removed = append(removed, entity)
+		} else {
+			kept = append(kept, entity)
+		}
+	}
+	t.elements = kept
+
+	return removed
+}
+
+// Past reports whether the bucket's window has ended by the system clock. A
+// Scheduler goes by its own Clock instead.
 func (t *TimespanBucket[T]) Past() bool {
-	return time.Now().After(t.endTime)
+	return t.pastAt(time.Now())
+}
+
+// pastAt reports whether the bucket's window has ended by now.
+func (t *TimespanBucket[T]) pastAt(now time.Time) bool {
+	return !t.endTime.After(now)
 }
 
 func (t *TimespanBucket[T]) String() string {
@@ -60,128 +150,1490 @@ func (t *TimespanBucket[T]) IsBefore(dueTime time.Time) bool {
 	return t.endTime.Before(dueTime)
 }
 
+// entry wraps a scheduled item with the due time it had when it was added so
+// that an item whose DueTime() drifts can't move around between buckets.
+type entry[T Schedulable] struct {
+	item    T
+	id      string
+	key     string
+	due     time.Time
+	expires time.Time
+	group   string
+	length  time.Duration
+	held    bool
+	seq     uint64
+	attempt int
+
+	interval       time.Duration
+	weekly         *WeeklyAt
+	maxOccurrences int
+	occurrences    int
+}
+
+func newEntry[T Schedulable](item T) *entry[T] {
+	e := &entry[T]{
+		item: item,
+		id:   item.Id(),
+		due:  item.DueTime(),
+	}
+	if expiring, ok := any(item).(Expiring); ok {
+		e.expires = expiring.ExpiresAt()
+	}
+	if recurring, ok := any(item).(Recurring); ok {
+		e.interval = recurring.Interval()
+	}
+	if weekly, ok := any(item).(WeeklyRecurring); ok {
+		e.weekly = weekly.WeeklyRule()
+	}
+	if limited, ok := any(item).(LimitedRecurring); ok {
+		e.maxOccurrences = limited.MaxOccurrences()
+	}
+	if grouped, ok := any(item).(Grouped); ok {
+		e.group = grouped.GroupId()
+	}
+	if spanning, ok := any(item).(Spanning); ok {
+		e.length = spanning.EndTime().Sub(e.due)
+	}
+	return e
+}
+
+// recurs reports whether the entry should be scheduled again after firing.
+func (e *entry[T]) recurs() bool {
+	return e.interval > 0 && (e.maxOccurrences <= 0 || e.occurrences < e.maxOccurrences)
+}
+
+func (e *entry[T]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+func (e *entry[T]) DueTime() time.Time {
+	return e.due
+}
+
+func (e *entry[T]) Id() string {
+	return e.id
+}
+
 type Scheduler[T Schedulable] struct {
-	buckets   []*TimespanBucket[T]
+	buckets   []*TimespanBucket[*entry[T]]
 	blockSize time.Duration
 	numBlocks int
 	ctx       context.Context
 	mutex     *sync.Mutex
+	clock     Clock
+	emptied   chan struct{}
+	deferred  []func()
+	onExpire  func(T)
+
+	onBucketActive func(start, end time.Time, size int)
+	onTick         func(now time.Time, depth int)
+
+	deferSpread time.Duration
+	order       Order
+	dedupe      DedupePolicy
+	seq         uint64
+
+	events       chan Event[T]
+	eventsClosed bool
+	traces       map[string][]chan TraceEvent
+	tracesClosed bool
+	// replacing is set while items are cancelled to make way for a
+	// replacement, so that their traces aren't ended
+	replacing bool
+
+	metrics metrics
+
+	overflow *TimespanBucket[*entry[T]]
+
+	lockOrder uint64
+
+	stopLoop context.CancelFunc
+	out      chan T
+	running  atomic.Int32
+
+	logger Logger
+	name   string
+
+	dedupeKey    func(T) string
+	onDue        func(T) error
+	retryBackoff func(attempt int) time.Duration
+	tickInterval time.Duration
+
+	reserved int
+	paused   bool
+	muted    bool
+
+	// groups counts the pending members of each group
+	groups map[string]int
+
+	maxLateness time.Duration
+	onDrop      func(T)
+
+	spreadRecurring bool
+	catchUp         CatchUpMode
+
+	// spans tracks occurrences of Spanning items until they end
+	spans []span[T]
+
+	loader      func(start, end time.Time) []T
+	loadedUntil time.Time
+
+	capacity int
+
+	deadLetter func(T, Reason)
+	router     func(T) string
+	handlers   map[string]func(T)
+
+	codec Codec[T]
+
+	ackTimeout time.Duration
+	inflight   map[uint64]time.Time
+
+	maxTailDepth int
+
+	flushAlignment time.Duration
+
+	lastUpdate time.Time
+
+	maxFuture time.Duration
+
+	heartbeatEvery time.Duration
+	heartbeat      chan time.Time
+
+	maxConcurrent int
+
+	dueGrid time.Duration
+
+	maxBlocks       int
+	growthExhausted GrowthExhausted
+
+	precise bool
+	wake    chan struct{}
+	armed   time.Time
+
+	regression ClockRegression
+	lastNow    time.Time
+
+	// asOf, when set, stands in for the clock for the duration of a DueAsOf
+	asOf time.Time
+
+	release      OverdueRelease
+	releaseStart time.Time
+	released     int
+}
+
+func NewScheduler[T Schedulable](ctx context.Context, blockSize time.Duration, numBlocks int, opts ...Option[T]) *Scheduler[T] {
+	s := newScheduler(ctx, blockSize, numBlocks, opts...)
+	s.extend(s.now())
+	return s
 }
 
-func NewScheduler[T Schedulable](ctx context.Context, blockSize time.Duration, numBlocks int) *Scheduler[T] {
-	buckets := make([]*TimespanBucket[T], 0)
+// NewSchedulerAt builds a scheduler whose head bucket starts at base rather
+// than now, e.g. to restore the exact layout that existed before a restart.
+func NewSchedulerAt[T Schedulable](ctx context.Context, base time.Time, blockSize time.Duration, numBlocks int, opts ...Option[T]) (*Scheduler[T], error) {
+	if base.IsZero() {
+		return nil, ErrZeroBase
+	}
+
+	s := newScheduler(ctx, blockSize, numBlocks, opts...)
+	s.extend(base)
+	return s, nil
+}
 
-	for i := 0; i < numBlocks; i++ {
-		startTime := time.Now().Add(time.Duration(i) * blockSize)
-		endTime := startTime.Add(blockSize)
-		buckets = append(buckets, NewTimespanBucket[T](startTime, endTime))
+func newScheduler[T Schedulable](ctx context.Context, blockSize time.Duration, numBlocks int, opts ...Option[T]) *Scheduler[T] {
+	if numBlocks < 1 {
+		numBlocks = 1
 	}
 
-	return &Scheduler[T]{
+	s := &Scheduler[T]{
 		ctx:       ctx,
-		buckets:   buckets,
+		buckets:   make([]*TimespanBucket[*entry[T]], 0),
 		blockSize: blockSize,
 		numBlocks: numBlocks,
 		mutex:     &sync.Mutex{},
+		clock:     realClock{},
+		logger:    nopLogger{},
+		lockOrder: schedulerCount.Add(1),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.name != "" {
+		s.logger = namedLogger{name: s.name, logger: s.logger}
+	}
+	s.lastUpdate = s.clock.Now()
+
+	return s
+}
+
+// later queues fn to run once the mutex has been released so that user
+// callbacks never run while holding the lock.
+func (s *Scheduler[T]) later(fn func()) {
+	s.deferred = append(s.deferred, fn)
+}
+
+func (s *Scheduler[T]) unlock() {
+	deferred := s.deferred
+	s.deferred = nil
+	s.mutex.Unlock()
+
+	for _, fn := range deferred {
+		fn()
+	}
+}
+
+// stores returns every bucket holding scheduled items: the live buckets in
+// time order followed by the overflow store, if there is one.
+func (s *Scheduler[T]) stores() []*TimespanBucket[*entry[T]] {
+	if s.overflow == nil {
+		return s.buckets
+	}
+	return append(s.buckets[:len(s.buckets):len(s.buckets)], s.overflow)
+}
+
+// extend appends buckets starting at startTime until there are numBlocks.
+func (s *Scheduler[T]) extend(startTime time.Time) {
+	for len(s.buckets) < s.numBlocks {
+		endTime := startTime.Add(s.blockSize)
+		s.appendBucket(startTime, endTime)
+		startTime = endTime
+	}
+}
+
+// appendBucket adds a bucket for [start, end) to the end of the horizon and
+// loads its window. Every bucket is created through here so that the window
+// loader sees every window that enters the horizon.
+func (s *Scheduler[T]) appendBucket(start, end time.Time) {
+	bucket := NewTimespanBucket[*entry[T]](start, end)
+	bucket.reserve(s.reserved)
+	s.buckets = append(s.buckets, bucket)
+	s.load(start, end)
+}
+
+// Reserve grows every bucket's capacity, including buckets created as the
+// horizon moves forward, to hold perBucket items without reallocating. It
+// only affects capacity; nothing is added to or removed from the schedule.
+func (s *Scheduler[T]) Reserve(perBucket int) {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	s.reserved = perBucket
+	for _, bucket := range s.buckets {
+		bucket.reserve(perBucket)
 	}
 }
 
 func (s *Scheduler[T]) update() {
-	overdueItems := make([]T, 0)
-	startIdx := 0
+	now := s.now()
 
-	for idx, bucket := range s.buckets {
-		if !bucket.Past() {
-			startIdx = idx
-			break
-		}
+	if s.paused && len(s.buckets) > 0 {
+		return
 	}
 
-	for i := 0; i < startIdx; i++ {
-		if s.buckets[i].Size() > 0 {
-			// pass
-			overdueItems = append(overdueItems, s.buckets[0].elements...)
-		}
+	s.lastUpdate = s.clock.Now()
+	s.observe(now)
+
+	if len(s.buckets) == 0 {
+		// Nothing to rotate from; start a fresh horizon at now
+		s.logger.Log(LevelWarn, "no buckets, rebuilding horizon", "start", now)
+		s.extend(now)
+		s.headChanged()
+		return
+	}
+
+	retired := 0
+	for retired < len(s.buckets) && s.buckets[retired].pastAt(now) {
+		retired++
 	}
 
-	s.buckets = s.buckets[startIdx:]
+	if retired == 0 {
+		s.settle(now)
+		return
+	}
 
 	currentEndTime := s.buckets[len(s.buckets)-1].endTime
-	newBuckets := make([]*TimespanBucket[T], 0)
-	for j := 0; j <= startIdx; j++ {
-		newBuckets = append(newBuckets, NewTimespanBucket[T](currentEndTime, currentEndTime.Add(s.blockSize)))
-		currentEndTime = currentEndTime.Add(s.blockSize)
+
+	// Items clamped into the old tail may belong in one of the new buckets
+	clamped := s.buckets[len(s.buckets)-1].removeWhere(func(e *entry[T]) bool {
+		return !e.due.Before(currentEndTime)
+	})
+
+	overdueItems := make([]*entry[T], 0)
+	for _, bucket := range s.buckets[:retired] {
+		overdueItems = append(overdueItems, bucket.elements...)
+	}
+	for _, entity := range overdueItems {
+		s.trace(TraceRotated, entity)
+	}
+
+	s.buckets = s.buckets[retired:]
+
+	if len(s.buckets) == 0 {
+		// The whole horizon is in the past, skip the blocks we slept through
+		// but stay aligned to the original bucket boundaries
+		currentEndTime = currentEndTime.Add(now.Sub(currentEndTime).Truncate(s.blockSize))
+		s.logger.Log(LevelInfo, "entire horizon expired, skipping ahead", "start", currentEndTime)
 	}
 
-	s.buckets = append(s.buckets, newBuckets...)
+	s.extend(currentEndTime)
 
 	s.buckets[0].elements = append(s.buckets[0].elements, overdueItems...)
+	s.buckets[0].sortByDue()
+
+	for _, entity := range clamped {
+		s.place(entity)
+	}
+	s.migrateOverflow()
+
+	s.logger.Log(LevelDebug, "rotated buckets", "retired", retired, "overdue", len(overdueItems), "head", s.buckets[0].startTime)
+	s.headChanged()
+	s.settle(now)
+
+	if s.onTick != nil {
+		onTick, depth := s.onTick, s.size()
+		s.later(func() {
+			onTick(now, depth)
+		})
+	}
+}
+
+// settle drops whatever has gone stale by now.
+func (s *Scheduler[T]) settle(now time.Time) {
+	s.dropLate(now)
+	if len(s.spans) > 0 {
+		s.pruneSpans(func(sp span[T]) bool {
+			return !sp.end.After(now)
+		})
+	}
+}
+
+// headChanged is called whenever a different bucket has become the head.
+func (s *Scheduler[T]) headChanged() {
+	if s.onBucketActive != nil {
+		onBucketActive := s.onBucketActive
+		head := s.buckets[0]
+		start, end, size := head.startTime, head.endTime, len(head.elements)
+		s.later(func() {
+			onBucketActive(start, end, size)
+		})
+	}
+}
+
+// Placement results returned by AddReminderAt for items that did not fall
+// inside a bucket window and were clamped into the first or last bucket, or
+// that were not scheduled at all.
+const (
+	ClampedTail = -1
+	ClampedHead = -2
+	Rejected    = -3
+)
+
+func (s *Scheduler[T]) AddReminder(entity T) error {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	s.update()
 
+	_, err := s.tryAdd(newEntry(entity))
+	return err
 }
 
-func (s *Scheduler[T]) AddReminder(entity T) {
+// AddReminderAt schedules entity and returns the index of the bucket it was
+// placed in, ClampedTail / ClampedHead if it was clamped or Rejected if it
+// couldn't be scheduled (AddReminder reports why).
+func (s *Scheduler[T]) AddReminderAt(entity T) int {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	defer s.unlock()
+
+	s.update()
+
+	idx, err := s.tryAdd(newEntry(entity))
+	if err != nil {
+		return Rejected
+	}
+	return idx
+}
+
+// tryAdd adds entity subject to the scheduler's dedupe policy and capacity.
+func (s *Scheduler[T]) tryAdd(entity *entry[T]) (int, error) {
+	s.snap(entity)
+	if err := s.check(entity); err != nil {
+		s.logger.Log(LevelDebug, "rejected item", "id", entity.id, "error", err)
+		if err == ErrGrowthExhausted && s.growthExhausted == DeadLetter {
+			s.lost(entity, BeyondHorizon)
+		}
+		return Rejected, err
+	}
+
+	key := s.keyOf(entity.item)
+	var replaced []*entry[T]
+	switch s.dedupe {
+	case DedupeReplace:
+		replaced = s.cancelKey(key)
+	case DedupeKeepEarliest, DedupeKeepLatest:
+		if existing := s.findKey(key); existing != nil {
+			keepNew := entity.due.Before(existing.due)
+			if s.dedupe == DedupeKeepLatest {
+				keepNew = entity.due.After(existing.due)
+			}
+			if !keepNew {
+				s.logger.Log(LevelDebug, "discarded duplicate item", "id", entity.id, "key", key, "due", entity.due)
+				return Rejected, nil
+			}
+			replaced = s.cancelKey(key)
+		}
+	}
+
+	s.grow(entity.due)
+	idx := s.add(entity)
+	for _, existing := range replaced {
+		s.traceLeft(existing.id)
+	}
+	return idx, nil
+}
+
+// cancelKey cancels every item with the given dedupe key to make way for a
+// replacement and returns them. Their traces are left open, since the
+// replacement usually carries on under the same Id; the caller ends them
+// with traceLeft once it has been added.
+func (s *Scheduler[T]) cancelKey(key string) []*entry[T] {
+	s.replacing = true
+	defer func() {
+		s.replacing = false
+	}()
+
+	cancelled := make([]*entry[T], 0)
+	for _, bucket := range s.stores() {
+		for _, existing := range bucket.removeWhere(func(e *entry[T]) bool { return e.key == key }) {
+			s.cancel(existing)
+			cancelled = append(cancelled, existing)
+		}
+	}
+	return cancelled
+}
 
+// Validate reports whether entity could be added right now, returning the
+// error AddReminder would, so a batch can be checked before any of it is
+// added. Nothing is added.
+func (s *Scheduler[T]) Validate(entity T) error {
+	s.mutex.Lock()
+	defer s.unlock()
 	s.update()
 
-	if s.buckets[0].IsAfter(entity.DueTime()) {
+	return s.check(newEntry(entity))
+}
+
+// check returns why entity can't be added, if it can't.
+func (s *Scheduler[T]) check(entity *entry[T]) error {
+	if entity.due.IsZero() {
+		return ErrZeroDue
+	}
+	if s.maxFuture > 0 && entity.due.Sub(s.now()) > s.maxFuture {
+		return ErrTooFar
+	}
+	if s.growthExhausted != Clamp && s.exhausted(entity.due) {
+		return ErrGrowthExhausted
+	}
+
+	key := s.keyOf(entity.item)
+	switch s.dedupe {
+	case DedupeReject:
+		if s.findKey(key) != nil {
+			return ErrDuplicateId
+		}
+	case DedupeReplace, DedupeKeepEarliest, DedupeKeepLatest:
+		if s.findKey(key) != nil {
+			// Either the existing item is replaced, freeing up the slot
+			// this one needs, or this one is discarded
+			return nil
+		}
+	}
+
+	if s.full() {
+		return ErrCapacity
+	}
+	if s.tailFull(entity.due) {
+		return ErrTailFull
+	}
+	return nil
+}
+
+// keyOf returns the key used to detect duplicates: the item's Id() unless
+// WithDedupeKey says otherwise.
+func (s *Scheduler[T]) keyOf(item T) string {
+	if s.dedupeKey != nil {
+		return s.dedupeKey(item)
+	}
+	return item.Id()
+}
+
+// Has reports whether an item with the given dedupe key is scheduled. That is
+// its Id() unless WithDedupeKey is in use.
+func (s *Scheduler[T]) Has(key string) bool {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	return s.findKey(key) != nil
+}
+
+func (s *Scheduler[T]) findKey(key string) *entry[T] {
+	for _, bucket := range s.stores() {
+		for _, entity := range bucket.elements {
+			if entity.key == key {
+				return entity
+			}
+		}
+	}
+	return nil
+}
+
+// TimeUntil returns how long until the item with the given id is due, negative
+// if it is overdue, with false if no such item is scheduled.
+func (s *Scheduler[T]) TimeUntil(id string) (time.Duration, bool) {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	entity := s.find(id)
+	if entity == nil {
+		return 0, false
+	}
+	return entity.due.Sub(s.now()), true
+}
+
+func (s *Scheduler[T]) find(id string) *entry[T] {
+	for _, bucket := range s.stores() {
+		for _, entity := range bucket.elements {
+			if entity.id == id {
+				return entity
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler[T]) add(entity *entry[T]) int {
+	s.seq++
+	entity.seq = s.seq
+	entity.key = s.keyOf(entity.item)
+	idx := s.place(entity)
+	s.metrics.scheduled.Add(1)
+	s.join(entity)
+	s.track(entity)
+	s.emit(Added, entity)
+	s.trace(TraceAdded, entity)
+	s.nudge(entity.due)
+	return idx
+}
+
+func (s *Scheduler[T]) place(entity *entry[T]) int {
+	result, idx := s.locate(entity.due)
+
+	switch result {
+	case ClampedHead:
+		s.logger.Log(LevelDebug, "clamped overdue item into head bucket", "id", entity.id, "due", entity.due)
+	case ClampedTail:
+		s.logger.Log(LevelDebug, "item due beyond horizon", "id", entity.id, "due", entity.due, "overflow", s.overflow != nil)
+	}
+
+	if result == ClampedTail && s.overflow != nil {
+		s.overflow.insertSorted(entity)
+		return result
+	}
+
+	if idx == 0 {
+		// The head bucket is kept in due order so Due can stop early
+		s.buckets[0].insertSorted(entity)
+	} else {
+		s.buckets[idx].AddEntity(entity)
+	}
+
+	return result
+}
+
+// locate returns the AddReminderAt result for dueTime along with the index of
+// the bucket an item due then belongs in.
+func (s *Scheduler[T]) locate(dueTime time.Time) (int, int) {
+	last := len(s.buckets) - 1
+
+	if s.buckets[0].IsAfter(dueTime) {
 		// Overdue? Put it at the head of the queue
-		s.buckets[0].AddEntity(entity)
-		return
+		return ClampedHead, 0
 	}
 
-	if s.buckets[len(s.buckets)-1].IsBefore(entity.DueTime()) {
+	if !s.buckets[last].endTime.After(dueTime) {
 		// Too far out? Shove it into the last bucket
-		s.buckets[len(s.buckets)-1].AddEntity(entity)
-		return
+		return ClampedTail, last
 	}
 
-	for _, bucket := range s.buckets {
-		if bucket.Contains(entity.DueTime()) {
-			bucket.AddEntity(entity)
-			return
+	for idx, bucket := range s.buckets {
+		if bucket.Contains(dueTime) {
+			return idx, idx
+		}
+	}
+
+	// Buckets are contiguous so this is unreachable, but never drop an item
+	return ClampedTail, last
+}
+
+// FireNow removes the item with the given Id and returns it as if it had come
+// due, whenever it is actually due and even if it is held, counting it as
+// fired and emitting a Fired event. If several items share the Id, the
+// soonest is fired. A recurring item is scheduled again for the occurrence
+// after the one that was forced, just as if it had fired on time. It returns
+// false if nothing with that Id is scheduled.
+func (s *Scheduler[T]) FireNow(id string) (T, bool) {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	var target *entry[T]
+	var from *TimespanBucket[*entry[T]]
+	for _, bucket := range s.stores() {
+		for _, entity := range bucket.elements {
+			if entity.id == id && (target == nil || entryBefore(entity, target)) {
+				target, from = entity, bucket
+			}
+		}
+	}
+	if target == nil {
+		var zero T
+		return zero, false
+	}
+
+	from.removeWhere(func(e *entry[T]) bool { return e == target })
+	s.logger.Log(LevelInfo, "fired item early", "id", id, "due", target.due)
+	s.fire([]*entry[T]{target})
+	s.signalIfEmpty()
+
+	return target.item, true
+}
+
+// Cancel removes every scheduled item with the given Id(), reporting whether
+// there were any. It ignores any WithDedupeKey key.
+func (s *Scheduler[T]) Cancel(id string) bool {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	cancelled := s.cancelId(id)
+	s.signalIfEmpty()
+
+	return cancelled
+}
+
+func (s *Scheduler[T]) cancelId(id string) bool {
+	cancelled := false
+	for _, bucket := range s.stores() {
+		for _, entity := range bucket.removeWhere(func(e *entry[T]) bool { return e.id == id }) {
+			s.cancel(entity)
+			cancelled = true
 		}
 	}
+	return cancelled
+}
+
+// RemoveWhere cancels every scheduled item for which pred returns true and
+// returns them, in due-time order, so their resources can be cleaned up.
+func (s *Scheduler[T]) RemoveWhere(pred func(T) bool) []T {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	removed := make([]*entry[T], 0)
+	for _, bucket := range s.stores() {
+		removed = append(removed, bucket.removeWhere(func(e *entry[T]) bool {
+			return pred(e.item)
+		})...)
+	}
+
+	sortByDue(removed)
+	for _, entity := range removed {
+		s.cancel(entity)
+	}
+	s.signalIfEmpty()
 
+	return items(removed)
 }
 
 func (s *Scheduler[T]) Due() []T {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	defer s.unlock()
+	s.update()
+
+	return items(s.takeDue(-1))
+}
+
+// DueAsOf is Due with now taken as the current time in place of the
+// scheduler's clock, for both rotating the buckets and deciding what is due,
+// so a recorded stream can be replayed through historical timestamps. The
+// clock is only overridden for the duration of the call, and rotation isn't
+// undone afterwards: if the clock is behind now, the next call that uses it
+// sees time go backwards and WithClockRegression applies.
+func (s *Scheduler[T]) DueAsOf(now time.Time) []T {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	s.asOf = now
+	defer func() { s.asOf = time.Time{} }()
+	s.update()
+
+	return items(s.takeDue(-1))
+}
+
+// DueLimit returns at most n due items, soonest first. Anything else that is
+// due stays scheduled and is returned by subsequent calls.
+func (s *Scheduler[T]) DueLimit(n int) []T {
+	s.mutex.Lock()
+	defer s.unlock()
 	s.update()
 
-	dueItems := make([]T, 0)
+	if n <= 0 {
+		return make([]T, 0)
+	}
+
+	return items(s.takeDue(n))
+}
+
+const drainBatch = 256
+
+// DrainWithin keeps collecting due items, a batch at a time and releasing the
+// lock between batches, until nothing is due or budget has elapsed.
+func (s *Scheduler[T]) DrainWithin(budget time.Duration) []T {
+	deadline := s.now().Add(budget)
+
+	drained := make([]T, 0)
+	for {
+		batch := s.DueLimit(drainBatch)
+		drained = append(drained, batch...)
+		if len(batch) < drainBatch || s.now().After(deadline) {
+			return drained
+		}
+	}
+}
+
+// takeDue removes up to limit due entries (all of them if limit < 0) from the
+// head bucket and returns them in delivery order.
+func (s *Scheduler[T]) takeDue(limit int) []*entry[T] {
+	if !s.delivering() {
+		return make([]*entry[T], 0)
+	}
 
+	now := s.now()
 	bucket := s.buckets[0]
 
-	removeIdxs := make([]int, 0)
-	for i, entity := range bucket.elements {
-		if entity.DueTime().Before(time.Now()) {
-			dueItems = append(dueItems, entity)
-			removeIdxs = append(removeIdxs, i)
+	// The head bucket is sorted by due time, so everything due is a prefix of
+	// it and the scan can stop at the first item that isn't
+	due := make([]*entry[T], 0)
+	taken := make(map[*entry[T]]bool)
+	scanned := 0
+	for _, entity := range bucket.elements {
+		if entity.due.After(now) {
+			break
+		}
+		if s.order == SoonestFirst && limit >= 0 && len(due) == limit && len(s.groups) == 0 {
+			break
+		}
+		scanned++
+		if entity.held {
+			continue
+		}
+		if entity.expired(now) {
+			taken[entity] = true
+			s.expire(entity)
+			continue
+		}
+		due = append(due, entity)
+	}
+
+	due = s.holdGroups(due)
+	sortForDelivery(s.order, due)
+	if limit >= 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	for _, entity := range due {
+		taken[entity] = true
+	}
+
+	if len(taken) == scanned {
+		bucket.elements = bucket.elements[scanned:]
+	} else {
+		bucket.removeWhere(func(e *entry[T]) bool {
+			return taken[e]
+		})
+	}
+	s.fire(due)
+	s.signalIfEmpty()
+
+	return due
+}
+
+// CatchUp reconciles the scheduler after a pause: every item that has come
+// due, whichever bucket it was waiting in, is removed and returned in due-time
+// order and the buckets are rebuilt as a fresh horizon starting at now. Items
+// that have expired by then are dropped, as Due would drop them, instead.
+// While the scheduler is paused or muted nothing is handed out and the items
+// that have come due are kept in the head bucket.
+func (s *Scheduler[T]) CatchUp() []T {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	now := s.now()
+	delivering := s.delivering()
+
+	due := make([]*entry[T], 0)
+	pending := make([]*entry[T], 0)
+	for _, entity := range s.entries() {
+		if entity.due.After(now) || entity.held || !delivering {
+			pending = append(pending, entity)
+		} else if entity.expired(now) {
+			s.expire(entity)
+		} else {
+			due = append(due, entity)
+		}
+	}
+
+	s.buckets = make([]*TimespanBucket[*entry[T]], 0, s.numBlocks)
+	s.extend(now)
+	s.clearOverflow()
+	for _, entity := range pending {
+		s.reschedule(entity)
+	}
+	s.headChanged()
+
+	sortByDue(due)
+	s.fire(due)
+	s.signalIfEmpty()
+
+	return items(due)
+}
+
+// SetResolution changes the width of every bucket to blockSize, keeping
+// numBlocks buckets, and re-bins everything that is scheduled into the new
+// horizon, which starts where the current head bucket does. Items keep their
+// due times; those that now fall outside the horizon grow it under
+// WithMaxBlocks, as far as it allows, and are otherwise clamped or moved to
+// the overflow bucket.
+func (s *Scheduler[T]) SetResolution(blockSize time.Duration) error {
+	if blockSize <= 0 {
+		return ErrBlockSize
+	}
+
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	s.blockSize = blockSize
+	pending := s.rebin(s.buckets[0].startTime, 0)
+
+	s.logger.Log(LevelInfo, "changed resolution", "blockSize", blockSize, "items", pending)
+	return nil
+}
+
+// Shift moves every pending item's due time, and the buckets along with
+// them, by delta, so a restored schedule can be slid to start from now.
+// Items that end up beyond the horizon grow it under WithMaxBlocks, as far as
+// it allows, and are otherwise clamped or overflow.
+func (s *Scheduler[T]) Shift(delta time.Duration) {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	// Shift the schedule as it was left before rotating it to now, or a
+	// schedule restored after a gap would be treated as overdue first
+	pending := s.rebin(s.buckets[0].startTime.Add(delta), delta)
+	s.logger.Log(LevelInfo, "shifted schedule", "delta", delta, "items", pending)
+	s.update()
+}
+
+// rebin rebuilds the buckets starting at start and places every pending item
+// back into them with delta added to its due time, returning how many there
+// were.
+func (s *Scheduler[T]) rebin(start time.Time, delta time.Duration) int {
+	pending := s.entries()
+
+	s.buckets = make([]*TimespanBucket[*entry[T]], 0, s.numBlocks)
+	s.extend(start)
+	s.clearOverflow()
+	for _, entity := range pending {
+		entity.due = entity.due.Add(delta)
+		s.reschedule(entity)
+		if delta != 0 {
+			s.trace(TraceMoved, entity)
+		}
+	}
+	for i := range s.spans {
+		s.spans[i].start = s.spans[i].start.Add(delta)
+		s.spans[i].end = s.spans[i].end.Add(delta)
+	}
+
+	s.headChanged()
+	return len(pending)
+}
+
+// TimeSinceLastUpdate returns how long ago, by the scheduler's clock, the
+// buckets were last brought up to date, which happens on almost every call
+// and on every pass of the loop started by Start. A value of several
+// blockSizes means nothing is driving the scheduler. Time spent paused
+// counts, since nothing is updated then; before the first update it is the
+// time since the scheduler was created.
+func (s *Scheduler[T]) TimeSinceLastUpdate() time.Duration {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	return s.clock.Now().Sub(s.lastUpdate)
+}
+
+// now returns the current time: the clock's, unless DueAsOf says otherwise.
+// It must be called with the lock held.
+func (s *Scheduler[T]) now() time.Time {
+	if !s.asOf.IsZero() {
+		return s.asOf
+	}
+	return s.clock.Now()
+}
+
+// resolution returns the current bucket width for callers outside the lock.
+func (s *Scheduler[T]) resolution() time.Duration {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	return s.blockSize
+}
+
+// Swap atomically replaces everything that is scheduled with newItems and
+// returns the items it replaced in due-time order. Each new item is added as
+// AddReminder would add it, so the horizon grows to fit it under
+// WithMaxBlocks and one the scheduler refuses, e.g. as a duplicate of an
+// earlier one, is left out.
+func (s *Scheduler[T]) Swap(newItems []T) []T {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	old := s.entries()
+	for _, bucket := range s.stores() {
+		bucket.elements = make([]*entry[T], 0)
+	}
+
+	sortByDue(old)
+	for _, entity := range old {
+		s.cancel(entity)
+	}
+
+	for _, item := range newItems {
+		s.tryAdd(newEntry(item))
+	}
+	s.signalIfEmpty()
+
+	return items(old)
+}
+
+// DeferOverdue pushes every overdue item (due before the head bucket's window)
+// back so it comes due at now+by, returning how many were moved. By default
+// they all share that due time; WithDeferSpread spaces them out instead.
+// Items are moved beyond the horizon as AddReminder would add them there, so
+// one that WithGrowthExhausted or WithMaxTailDepth would refuse stays put.
+func (s *Scheduler[T]) DeferOverdue(by time.Duration) int {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	head := s.buckets[0]
+	overdue := head.removeWhere(func(e *entry[T]) bool {
+		return e.due.Before(head.startTime) && !e.held
+	})
+	sortByDue(overdue)
+
+	base := s.now().Add(by)
+	moved := 0
+	for i, entity := range overdue {
+		from := entity.due
+		to := base
+		if s.deferSpread > 0 {
+			to = base.Add(s.deferSpread * time.Duration(i) / time.Duration(len(overdue)))
+		}
+		if s.refuses(to) != nil {
+			s.place(entity)
+			continue
+		}
+		entity.due = to
+		s.reschedule(entity)
+		s.retrack(entity, from)
+		s.trace(TraceMoved, entity)
+		moved++
+	}
+
+	return moved
+}
+
+// Defer moves every item in the live bucket at index forward by by, re-binning
+// them, and returns how many were moved, e.g. to shed load from an overloaded
+// bucket. Items are pushed past the horizon as AddReminder would add them
+// there, growing it under WithMaxBlocks, and one that WithGrowthExhausted or
+// WithMaxTailDepth would refuse stays put, as do held items. Nothing is
+// moved if index is out of range or by isn't positive.
+func (s *Scheduler[T]) Defer(index int, by time.Duration) int {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	if index < 0 || index >= len(s.buckets) || by <= 0 {
+		return 0
+	}
+
+	moved := s.buckets[index].removeWhere(func(e *entry[T]) bool {
+		return !e.held
+	})
+
+	deferred := 0
+	for _, entity := range moved {
+		from := entity.due
+		if s.refuses(from.Add(by)) != nil {
+			s.place(entity)
+			continue
+		}
+		entity.due = from.Add(by)
+		s.reschedule(entity)
+		s.retrack(entity, from)
+		s.trace(TraceMoved, entity)
+		deferred++
+	}
+
+	s.logger.Log(LevelInfo, "deferred bucket", "index", index, "by", by, "moved", deferred)
+	return deferred
+}
+
+// entries returns every scheduled entry in bucket order.
+func (s *Scheduler[T]) entries() []*entry[T] {
+	all := make([]*entry[T], 0)
+	for _, bucket := range s.stores() {
+		all = append(all, bucket.elements...)
+	}
+	return all
+}
+
+func sortByDue[T Schedulable](entries []*entry[T]) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.due.Equal(b.due) {
+			return a.id < b.id
+		}
+		return a.due.Before(b.due)
+	})
+}
+
+func items[T Schedulable](entries []*entry[T]) []T {
+	result := make([]T, 0, len(entries))
+	for _, entity := range entries {
+		result = append(result, entity.item)
+	}
+	return result
+}
+
+// fire records that entries have been delivered and schedules recurring ones
+// again. The entries must already have been removed from their buckets.
+func (s *Scheduler[T]) fire(fired []*entry[T]) {
+	for _, entity := range fired {
+		entity.occurrences++
+		s.landed(entity)
+		s.metrics.fired.Add(1)
+		s.emit(Fired, entity)
+
+		if entity.recurs() {
+			entity.due = s.nextOccurrence(entity)
+			s.reschedule(entity)
+			s.track(entity)
+			s.trace(TraceFired, entity)
+			continue
+		}
+
+		s.leave(entity)
+		if entity.interval > 0 {
+			s.emit(Completed, entity)
+		}
+		s.trace(TraceFired, entity)
+	}
+}
+
+// cancel records that entity was removed without being delivered. It must
+// already have been removed from its bucket.
+func (s *Scheduler[T]) cancel(entity *entry[T]) {
+	s.metrics.cancelled.Add(1)
+	s.landed(entity)
+	s.leave(entity)
+	s.untrack(entity)
+	s.emit(Removed, entity)
+	s.trace(TraceCancelled, entity)
+}
+
+func (s *Scheduler[T]) expire(entity *entry[T]) {
+	s.logger.Log(LevelDebug, "dropped expired item", "id", entity.id, "expired", entity.expires)
+	s.metrics.expired.Add(1)
+	s.landed(entity)
+	s.leave(entity)
+	s.untrack(entity)
+	s.emit(Removed, entity)
+	s.trace(TraceExpired, entity)
+	s.lost(entity, Expired)
+	if s.onExpire != nil {
+		onExpire := s.onExpire
+		s.later(func() {
+			onExpire(entity.item)
+		})
+	}
+}
+
+// Check verifies the scheduler's internal invariants and returns an error
+// describing the first violation it finds.
+func (s *Scheduler[T]) Check() error {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	// There can be more after WithMaxBlocks growth or ImportLayout, never fewer
+	if len(s.buckets) < s.numBlocks {
+		return fmt.Errorf("schedule: have %d buckets, want at least %d", len(s.buckets), s.numBlocks)
+	}
+
+	last := len(s.buckets) - 1
+	for idx, bucket := range s.buckets {
+		if !bucket.endTime.After(bucket.startTime) {
+			return fmt.Errorf("schedule: bucket %d window is inverted or empty (%s)", idx, bucket)
+		}
+		if idx > 0 && !s.buckets[idx-1].endTime.Equal(bucket.startTime) {
+			return fmt.Errorf("schedule: bucket %d starts at %s but bucket %d ends at %s", idx, bucket.startTime, idx-1, s.buckets[idx-1].endTime)
+		}
+
+		for _, entity := range bucket.elements {
+			if idx > 0 && entity.due.Before(bucket.startTime) {
+				return fmt.Errorf("schedule: %s due at %s is before bucket %d (%s)", entity.id, entity.due, idx, bucket)
+			}
+			if idx < last && !entity.due.Before(bucket.endTime) {
+				return fmt.Errorf("schedule: %s due at %s is after bucket %d (%s)", entity.id, entity.due, idx, bucket)
+			}
+		}
+	}
+
+	head := s.buckets[0].elements
+	for i := 1; i < len(head); i++ {
+		if head[i].due.Before(head[i-1].due) {
+			return fmt.Errorf("schedule: head bucket is out of due order at %s", head[i].id)
+		}
+	}
+
+	if s.overflow != nil {
+		overflow := s.overflow.elements
+		for i, entity := range overflow {
+			if entity.due.Before(s.buckets[last].endTime) {
+				return fmt.Errorf("schedule: overflow item %s due at %s is within the horizon", entity.id, entity.due)
+			}
+			if i > 0 && entity.due.Before(overflow[i-1].due) {
+				return fmt.Errorf("schedule: overflow is out of due order at %s", entity.id)
+			}
+		}
+	}
+
+	return nil
+}
+
+// NextDueTime returns the due time of the soonest scheduled item, with false
+// if nothing is scheduled.
+func (s *Scheduler[T]) NextDueTime() (time.Time, bool) {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	return s.nextDue()
+}
+
+// TimeUntilNext returns how long until the soonest item is due, or zero if it
+// already is, with false if nothing is scheduled.
+func (s *Scheduler[T]) TimeUntilNext() (time.Duration, bool) {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	next, ok := s.nextDue()
+	if !ok {
+		return 0, false
+	}
+
+	wait := next.Sub(s.now())
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+// NextActivity returns when the scheduler will next have something to do:
+// the sooner of the next due time and the end of the head bucket, when the
+// buckets rotate. It returns false if nothing is scheduled or the scheduler is
+// paused, since there is nothing to wake up for.
+func (s *Scheduler[T]) NextActivity() (time.Time, bool) {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	next, ok := s.nextDue()
+	if !ok || s.paused {
+		return time.Time{}, false
+	}
+
+	if boundary := s.buckets[0].endTime; boundary.Before(next) {
+		return boundary, true
+	}
+	return next, true
+}
+
+// Range returns the earliest and latest due times of everything scheduled,
+// with ok false if nothing is.
+func (s *Scheduler[T]) Range() (earliest, latest time.Time, ok bool) {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	for _, bucket := range s.stores() {
+		for _, entity := range bucket.elements {
+			if !ok || entity.due.Before(earliest) {
+				earliest = entity.due
+			}
+			if !ok || entity.due.After(latest) {
+				latest = entity.due
+			}
+			ok = true
+		}
+	}
+
+	return earliest, latest, ok
+}
+
+func (s *Scheduler[T]) nextDue() (time.Time, bool) {
+	// Buckets are in time order so the first non-empty one holds the soonest
+	for _, bucket := range s.stores() {
+		if len(bucket.elements) == 0 {
+			continue
+		}
+
+		next := bucket.elements[0].due
+		for _, entity := range bucket.elements[1:] {
+			if entity.due.Before(next) {
+				next = entity.due
+			}
+		}
+		return next, true
+	}
+
+	return time.Time{}, false
+}
+
+// Len returns the number of items currently scheduled.
+func (s *Scheduler[T]) Len() int {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	return s.size()
+}
+
+// Ids returns the Id of every item currently scheduled, in due-time order
+// with ties broken by Id. An Id scheduled more than once, which only
+// DedupeAllow permits, is listed once, at its soonest.
+func (s *Scheduler[T]) Ids() []string {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	pending := s.entries()
+	sortByDue(pending)
+
+	ids := make([]string, 0, len(pending))
+	seen := make(map[string]bool, len(pending))
+	for _, entity := range pending {
+		if !seen[entity.id] {
+			seen[entity.id] = true
+			ids = append(ids, entity.id)
+		}
+	}
+	return ids
+}
+
+func (s *Scheduler[T]) size() int {
+	total := 0
+	for _, bucket := range s.stores() {
+		total += bucket.Size()
+	}
+	return total
+}
+
+// Windows returns the {start, end} of every live bucket, head first.
+func (s *Scheduler[T]) Windows() [][2]time.Time {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	windows := make([][2]time.Time, 0, len(s.buckets))
+	for _, bucket := range s.buckets {
+		windows = append(windows, [2]time.Time{bucket.startTime, bucket.endTime})
+	}
+	return windows
+}
+
+// Occupancy returns the number of items in every live bucket, in the same
+// order as Windows.
+func (s *Scheduler[T]) Occupancy() []int {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	occupancy := make([]int, 0, len(s.buckets))
+	for _, bucket := range s.buckets {
+		occupancy = append(occupancy, bucket.Size())
+	}
+	return occupancy
+}
+
+// BucketItems returns a copy of the items in the live bucket at index, in
+// due-time order, with false if there is no such bucket. Indexes match
+// Windows and Occupancy.
+func (s *Scheduler[T]) BucketItems(index int) ([]T, bool) {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	if index < 0 || index >= len(s.buckets) {
+		return nil, false
+	}
+
+	entries := append([]*entry[T](nil), s.buckets[index].elements...)
+	sortByDue(entries)
+	return items(entries), true
+}
+
+// PreviewBucket returns a snapshot of the items that will come due while the
+// live bucket at index is the head, in due-time order, so they can be
+// prepared for ahead of time. For the head that includes anything overdue;
+// items merely clamped into the tail bucket from beyond the horizon are left
+// out, as they won't be due then. Nothing is removed, and an out of range
+// index returns nothing.
+func (s *Scheduler[T]) PreviewBucket(index int) []T {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	if index < 0 || index >= len(s.buckets) {
+		return make([]T, 0)
+	}
+
+	bucket := s.buckets[index]
+	preview := make([]*entry[T], 0, len(bucket.elements))
+	for _, entity := range bucket.elements {
+		if entity.due.Before(bucket.endTime) {
+			preview = append(preview, entity)
+		}
+	}
+	sortByDue(preview)
+	return items(preview)
+}
+
+// Page returns up to limit scheduled items, skipping the first offset, in
+// global due-time order. Pages are a point-in-time view: items firing or being
+// added between calls will shift what later pages contain.
+func (s *Scheduler[T]) Page(offset, limit int) []T {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	page := make([]T, 0)
+	if offset < 0 || limit <= 0 {
+		return page
+	}
+
+	// Buckets are already in time order, so only the buckets the page
+	// actually overlaps need sorting
+	for _, bucket := range s.stores() {
+		if offset >= len(bucket.elements) {
+			offset -= len(bucket.elements)
+			continue
 		}
+
+		sorted := append([]*entry[T](nil), bucket.elements...)
+		sortByDue(sorted)
+		for _, entity := range sorted[offset:] {
+			if len(page) == limit {
+				return page
+			}
+			page = append(page, entity.item)
+		}
+		offset = 0
+	}
+
+	return page
+}
+
+// WaitEmpty blocks until every scheduled item has been handed out, returning
+// ctx.Err() if ctx is done first. Nothing stops new items being added once it
+// has returned, so the scheduler may no longer be empty by the time it does.
+func (s *Scheduler[T]) WaitEmpty(ctx context.Context) error {
+	s.mutex.Lock()
+	if s.size() == 0 {
+		s.mutex.Unlock()
+		return nil
+	}
+	if s.emptied == nil {
+		s.emptied = make(chan struct{})
+	}
+	emptied := s.emptied
+	s.mutex.Unlock()
+
+	select {
+	case <-emptied:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// signalIfEmpty wakes any WaitEmpty callers once the last item is gone. It
+// must be called with the mutex held after removing items.
+func (s *Scheduler[T]) signalIfEmpty() {
+	if s.emptied != nil && s.size() == 0 {
+		close(s.emptied)
+		s.emptied = nil
 	}
+}
+
+// String describes the scheduler's name, buckets and size.
+func (s *Scheduler[T]) String() string {
+	s.mutex.Lock()
+	defer s.unlock()
 
-	for i, idx := range removeIdxs {
-		// Do the truffle shuffle!
-		realIdx := idx - i // we have removed i elements so we need to subtract that from the index
-		bucket.elements = append(bucket.elements[:realIdx], bucket.elements[realIdx+1:]...)
+	name := "Scheduler"
+	if s.name != "" {
+		name = fmt.Sprintf("Scheduler %q", s.name)
 	}
 
-	return dueItems
+	start := time.Time{}
+	if len(s.buckets) > 0 {
+		start = s.buckets[0].startTime
+	}
+	return fmt.Sprintf("%s: %d x %s buckets from %s, %d items", name, len(s.buckets), s.blockSize, start, s.size())
 }
 
 func (s *Scheduler[T]) Dump() {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	defer s.unlock()
 
 	s.update()
 
 	for _, bucket := range s.buckets {
 		fmt.Printf("%s (%d)\n", bucket.String(), bucket.Size())
 		for _, entity := range bucket.elements {
-			fmt.Printf(" * %s @ %s\n", entity.Id(), entity.DueTime)
+			fmt.Printf(" * %s @ %s\n", entity.Id(), entity.DueTime())
+		}
+	}
+
+	if s.overflow != nil {
+		fmt.Printf("Overflow (%d)\n", s.overflow.Size())
+		for _, entity := range s.overflow.elements {
+			fmt.Printf(" * %s @ %s\n", entity.Id(), entity.DueTime())
 		}
 	}
 }
@@ -1 +1,1563 @@
 package schedule
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testItem struct {
+	id  string
+	due time.Time
+}
+
+func (t testItem) DueTime() time.Time {
+	return t.due
+}
+
+func (t testItem) Id() string {
+	return t.id
+}
+
+func TestAddReminderAt(t *testing.T) {
+	s := NewScheduler[testItem](context.Background(), time.Minute, 5)
+	now := time.Now()
+
+	cases := []struct {
+		due  time.Time
+		want int
+	}{
+		{now.Add(30 * time.Second), 0},
+		{now.Add(150 * time.Second), 2},
+		{now.Add(-time.Hour), ClampedHead},
+		{now.Add(24 * time.Hour), ClampedTail},
+	}
+
+	for _, c := range cases {
+		if got := s.AddReminderAt(testItem{id: "x", due: c.due}); got != c.want {
+			t.Errorf("AddReminderAt(%s) = %d, want %d", c.due.Sub(now), got, c.want)
+		}
+	}
+}
+
+func TestDueLimit(t *testing.T) {
+	s := NewScheduler[testItem](context.Background(), time.Minute, 5)
+	now := time.Now()
+
+	offsets := map[string]time.Duration{"a": 5, "b": 4, "c": 3, "d": 2, "e": 1}
+	for _, id := range []string{"c", "a", "d", "b", "e"} {
+		s.AddReminder(testItem{id: id, due: now.Add(-offsets[id] * time.Millisecond)})
+	}
+
+	var got []string
+	for _, batch := range [][]testItem{s.DueLimit(2), s.DueLimit(2), s.DueLimit(2), s.DueLimit(2)} {
+		if len(batch) > 2 {
+			t.Fatalf("DueLimit(2) returned %d items", len(batch))
+		}
+		for _, item := range batch {
+			got = append(got, item.id)
+		}
+	}
+
+	want := []string{"a", "b", "c", "d", "e"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWaitEmpty(t *testing.T) {
+	s := NewScheduler[testItem](context.Background(), time.Minute, 5)
+	s.AddReminder(testItem{id: "a", due: time.Now().Add(-time.Second)})
+
+	if s.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", s.Len())
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.WaitEmpty(context.Background())
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("WaitEmpty returned early: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	s.Due()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("WaitEmpty() = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitEmpty did not return after the scheduler emptied")
+	}
+}
+
+func TestWaitEmptyCancelled(t *testing.T) {
+	s := NewScheduler[testItem](context.Background(), time.Minute, 5)
+	s.AddReminder(testItem{id: "a", due: time.Now().Add(time.Hour)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := s.WaitEmpty(ctx); err != context.Canceled {
+		t.Fatalf("WaitEmpty() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWindows(t *testing.T) {
+	s := NewScheduler[testItem](context.Background(), time.Minute, 5)
+	s.AddReminder(testItem{id: "a", due: time.Now().Add(90 * time.Second)})
+
+	windows := s.Windows()
+	occupancy := s.Occupancy()
+	if len(windows) != len(occupancy) {
+		t.Fatalf("%d windows but %d occupancy entries", len(windows), len(occupancy))
+	}
+
+	for i, w := range windows {
+		if w[1].Sub(w[0]) != time.Minute {
+			t.Errorf("window %d spans %s, want %s", i, w[1].Sub(w[0]), time.Minute)
+		}
+		if i > 0 && !windows[i-1][1].Equal(w[0]) {
+			t.Errorf("window %d starts at %s, previous ended at %s", i, w[0], windows[i-1][1])
+		}
+	}
+
+	if occupancy[1] != 1 {
+		t.Errorf("occupancy = %v, want the item in bucket 1", occupancy)
+	}
+}
+
+func TestClampedNeverFiresEarly(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	due := start.Add(time.Minute + 300*time.Millisecond)
+	s.AddReminder(testItem{id: "far", due: due})
+
+	// Step through every bucket the item is clamped into on its way in
+	for clock.Now().Before(due) {
+		if fired := s.Due(); len(fired) != 0 {
+			t.Fatalf("fired %v at %s, before its due time %s", fired, clock.Now().Sub(start), due.Sub(start))
+		}
+		clock.Advance(100 * time.Millisecond)
+	}
+
+	if fired := s.Due(); len(fired) != 1 {
+		t.Errorf("Due() = %v once the due time passed, want [far]", fired)
+	}
+}
+
+func TestBucketItems(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "b", due: start.Add(1700 * time.Millisecond)})
+	s.AddReminder(testItem{id: "a", due: start.Add(1200 * time.Millisecond)})
+	s.AddReminder(testItem{id: "c", due: start.Add(2500 * time.Millisecond)})
+
+	got, ok := s.BucketItems(1)
+	if !ok || len(got) != 2 || got[0].id != "a" || got[1].id != "b" {
+		t.Errorf("BucketItems(1) = %v, %t, want [a b], true", got, ok)
+	}
+
+	got[0] = testItem{id: "z"}
+	if again, _ := s.BucketItems(1); again[0].id != "a" {
+		t.Error("changing the returned slice changed the bucket")
+	}
+
+	for _, index := range []int{-1, 4} {
+		if _, ok := s.BucketItems(index); ok {
+			t.Errorf("BucketItems(%d) ok = true", index)
+		}
+	}
+}
+
+func TestPreviewBucket(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "overdue", due: start.Add(-time.Minute)})
+	s.AddReminder(testItem{id: "head", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "tail", due: start.Add(3500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "clamped", due: start.Add(time.Hour)})
+
+	ids := func(items []testItem) string {
+		var got []string
+		for _, item := range items {
+			got = append(got, item.id)
+		}
+		return fmt.Sprint(got)
+	}
+
+	if got := ids(s.PreviewBucket(0)); got != "[overdue head]" {
+		t.Errorf("PreviewBucket(0) = %s, want [overdue head]", got)
+	}
+	if got := ids(s.PreviewBucket(3)); got != "[tail]" {
+		t.Errorf("PreviewBucket(3) = %s, want [tail]", got)
+	}
+	if got := s.PreviewBucket(4); len(got) != 0 {
+		t.Errorf("PreviewBucket(4) = %v, want nothing", got)
+	}
+	if s.Len() != 4 {
+		t.Errorf("Len() = %d after previewing, want 4", s.Len())
+	}
+}
+
+func TestDueAfterHorizonPassed(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(1500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(time.Hour)})
+
+	clock.Advance(10 * time.Hour)
+
+	due := s.Due()
+	if len(due) != 2 {
+		t.Fatalf("Due() returned %d items, want 2", len(due))
+	}
+
+	windows := s.Windows()
+	if len(windows) != 4 {
+		t.Fatalf("got %d buckets, want 4", len(windows))
+	}
+	now := clock.Now()
+	if windows[0][0].After(now) || !windows[0][1].After(now) {
+		t.Errorf("head bucket %s -> %s does not contain now (%s)", windows[0][0], windows[0][1], now)
+	}
+	if windows[0][0].Sub(start)%time.Second != 0 {
+		t.Errorf("head bucket start %s is not aligned to the original layout", windows[0][0])
+	}
+}
+
+func TestUpdateRebuildsEmptyBuckets(t *testing.T) {
+	clock := NewFakeClock(time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC))
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+	s.buckets = s.buckets[:0]
+
+	if due := s.Due(); len(due) != 0 {
+		t.Fatalf("Due() returned %d items, want 0", len(due))
+	}
+	if len(s.buckets) != 4 {
+		t.Fatalf("got %d buckets after rebuild, want 4", len(s.buckets))
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "late", due: start.Add(3500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "early", due: start.Add(-time.Minute)})
+	s.AddReminder(testItem{id: "middle", due: start.Add(1500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "future", due: start.Add(time.Hour)})
+
+	clock.Advance(time.Minute + 250*time.Millisecond)
+
+	due := s.CatchUp()
+	want := []string{"early", "middle", "late"}
+	if len(due) != len(want) {
+		t.Fatalf("CatchUp() returned %d items, want %d", len(due), len(want))
+	}
+	for i, id := range want {
+		if due[i].id != id {
+			t.Errorf("CatchUp()[%d] = %s, want %s", i, due[i].id, id)
+		}
+	}
+
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d after catch up, want 1", s.Len())
+	}
+	if windows := s.Windows(); !windows[0][0].Equal(clock.Now()) {
+		t.Errorf("head bucket starts at %s, want %s", windows[0][0], clock.Now())
+	}
+}
+
+func TestCatchUpMuted(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(1500 * time.Millisecond)})
+	s.Mute()
+	clock.Advance(time.Minute)
+
+	if due := s.CatchUp(); len(due) != 0 {
+		t.Fatalf("CatchUp() returned %d items while muted, want 0", len(due))
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d after catch up while muted, want 2", s.Len())
+	}
+
+	s.Unmute()
+	if due := s.CatchUp(); len(due) != 2 {
+		t.Errorf("CatchUp() returned %d items once unmuted, want 2", len(due))
+	}
+}
+
+func TestCatchUpExpired(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	var lost []string
+	s := NewScheduler[expiringItem](context.Background(), time.Second, 4,
+		WithClock[expiringItem](clock),
+		WithDeadLetter(func(item expiringItem, reason Reason) {
+			lost = append(lost, item.id+": "+reason.String())
+		}),
+	)
+
+	s.AddReminder(expiringItem{testItem{id: "expired", due: start.Add(500 * time.Millisecond)}, start.Add(time.Second)})
+	s.AddReminder(expiringItem{testItem{id: "live", due: start.Add(1500 * time.Millisecond)}, start.Add(time.Hour)})
+	clock.Advance(time.Minute)
+
+	if due := s.CatchUp(); len(due) != 1 || due[0].id != "live" {
+		t.Errorf("CatchUp() = %v, want only the item that hasn't expired", due)
+	}
+	if want := "[expired: expired]"; fmt.Sprint(lost) != want {
+		t.Errorf("dead letters = %v, want %s", lost, want)
+	}
+	if s.TotalExpired() != 1 {
+		t.Errorf("TotalExpired() = %d, want 1", s.TotalExpired())
+	}
+}
+
+type expiringItem struct {
+	testItem
+	expires time.Time
+}
+
+func (e expiringItem) ExpiresAt() time.Time {
+	return e.expires
+}
+
+func TestDueDropsExpiredItems(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	var expired []string
+	s := NewScheduler[expiringItem](context.Background(), time.Second, 4,
+		WithClock[expiringItem](clock),
+		WithOnExpire(func(item expiringItem) {
+			expired = append(expired, item.id)
+		}),
+	)
+
+	due := start.Add(500 * time.Millisecond)
+	now := start.Add(time.Second)
+	s.AddReminder(expiringItem{testItem{id: "at-now", due: due}, now})
+	s.AddReminder(expiringItem{testItem{id: "after-now", due: due}, now.Add(time.Nanosecond)})
+	s.AddReminder(expiringItem{testItem{id: "never", due: due}, time.Time{}})
+
+	clock.Set(now)
+
+	delivered := s.Due()
+	if len(delivered) != 2 || delivered[0].id != "after-now" || delivered[1].id != "never" {
+		t.Errorf("Due() = %v, want [after-now never]", delivered)
+	}
+	if len(expired) != 1 || expired[0] != "at-now" {
+		t.Errorf("expired = %v, want [at-now]", expired)
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", s.Len())
+	}
+}
+
+func TestSwap(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "old-b", due: start.Add(2 * time.Second)})
+	s.AddReminder(testItem{id: "old-a", due: start.Add(time.Second)})
+
+	old := s.Swap([]testItem{
+		{id: "new-a", due: start.Add(500 * time.Millisecond)},
+		{id: "new-b", due: start.Add(time.Hour)},
+		{id: "new-c", due: start.Add(-time.Hour)},
+	})
+
+	if len(old) != 2 || old[0].id != "old-a" || old[1].id != "old-b" {
+		t.Errorf("Swap() = %v, want [old-a old-b]", old)
+	}
+	if occupancy := s.Occupancy(); occupancy[0] != 2 || occupancy[3] != 1 {
+		t.Errorf("occupancy = %v, want 2 items at the head and 1 clamped to the tail", occupancy)
+	}
+}
+
+func TestSwapAdmission(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 2,
+		WithClock[testItem](clock),
+		WithDedupe[testItem](DedupeReject),
+		WithMaxBlocks[testItem](8),
+	)
+	s.AddReminder(testItem{id: "old", due: start.Add(500 * time.Millisecond)})
+
+	s.Swap([]testItem{
+		{id: "a", due: start.Add(500 * time.Millisecond)},
+		{id: "a", due: start.Add(1500 * time.Millisecond)},
+		{id: "far", due: start.Add(5500 * time.Millisecond)},
+	})
+
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d, want the duplicate of a refused", s.Len())
+	}
+	if occupancy := s.Occupancy(); !reflect.DeepEqual(occupancy, []int{1, 0, 0, 0, 0, 1}) {
+		t.Errorf("Occupancy() = %v, want the horizon grown to reach far", occupancy)
+	}
+}
+
+func TestDeferOverdue(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+
+	for _, spread := range []time.Duration{0, 4 * time.Second} {
+		clock := NewFakeClock(start)
+		s := NewScheduler[testItem](context.Background(), time.Second, 10,
+			WithClock[testItem](clock),
+			WithDeferSpread[testItem](spread),
+		)
+
+		for _, id := range []string{"a", "b", "c", "d"} {
+			s.AddReminder(testItem{id: id, due: start.Add(-time.Minute)})
+		}
+		s.AddReminder(testItem{id: "future", due: start.Add(5 * time.Second)})
+
+		if moved := s.DeferOverdue(2 * time.Second); moved != 4 {
+			t.Fatalf("spread %s: DeferOverdue() = %d, want 4", spread, moved)
+		}
+		if due := s.Due(); len(due) != 0 {
+			t.Fatalf("spread %s: %d items still due after deferring", spread, len(due))
+		}
+
+		clock.Advance(2 * time.Second)
+		want := 4
+		if spread > 0 {
+			want = 1
+		}
+		if due := s.Due(); len(due) != want {
+			t.Errorf("spread %s: %d items due at now+by, want %d", spread, len(due), want)
+		}
+	}
+}
+
+func TestDefer(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(1200 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(1800 * time.Millisecond)})
+	s.AddReminder(testItem{id: "c", due: start.Add(2500 * time.Millisecond)})
+
+	for _, c := range []struct {
+		index int
+		by    time.Duration
+	}{{-1, time.Second}, {4, time.Second}, {1, 0}} {
+		if n := s.Defer(c.index, c.by); n != 0 {
+			t.Errorf("Defer(%d, %s) = %d, want 0", c.index, c.by, n)
+		}
+	}
+
+	if n := s.Defer(1, 500*time.Millisecond); n != 2 {
+		t.Errorf("Defer(1, 500ms) = %d, want 2", n)
+	}
+	if err := s.Check(); err != nil {
+		t.Fatalf("Check() = %v", err)
+	}
+	if got := fmt.Sprint(s.Occupancy()); got != "[0 1 2 0]" {
+		t.Errorf("Occupancy() = %s, want [0 1 2 0]", got)
+	}
+	if wait, _ := s.TimeUntil("b"); wait != 2300*time.Millisecond {
+		t.Errorf("TimeUntil(b) = %s, want 2.3s", wait)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewScheduler[testItem](ctx, time.Second, 4, WithClock[testItem](clock))
+
+	events := s.Events()
+	s.AddReminder(testItem{id: "a", due: start})
+	s.AddReminder(testItem{id: "b", due: start.Add(time.Minute)})
+	s.Due()
+	s.Swap(nil)
+	cancel()
+
+	want := []struct {
+		kind EventKind
+		id   string
+	}{
+		{Added, "a"},
+		{Added, "b"},
+		{Fired, "a"},
+		{Removed, "b"},
+	}
+
+	for _, w := range want {
+		select {
+		case event := <-events:
+			if event.Kind != w.kind || event.Item.id != w.id {
+				t.Errorf("got %s %s, want %s %s", event.Kind, event.Item.id, w.kind, w.id)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s %s", w.kind, w.id)
+		}
+	}
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("unexpected extra event")
+		}
+	case <-time.After(time.Second):
+		t.Error("events channel was not closed after the context was cancelled")
+	}
+}
+
+func TestBatchEvents(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewScheduler[testItem](ctx, time.Second, 4, WithClock[testItem](clock))
+
+	batches := s.BatchEvents(context.Background(), 3, 100*time.Millisecond)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		s.AddReminder(testItem{id: id, due: start.Add(time.Second)})
+	}
+
+	receive := func() []Event[testItem] {
+		select {
+		case batch := <-batches:
+			return batch
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a batch")
+			return nil
+		}
+	}
+
+	if batch := receive(); len(batch) != 3 || batch[0].Item.id != "a" || batch[2].Item.id != "c" {
+		t.Errorf("first batch = %v, want a full batch of a, b, c", batch)
+	}
+
+	// The flush may race with d being read off the feed, in which case it
+	// goes out on the following one
+	var flushed []Event[testItem]
+	for i := 0; i < 10 && flushed == nil; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(100 * time.Millisecond)
+		select {
+		case flushed = <-batches:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if len(flushed) != 1 || flushed[0].Item.id != "d" {
+		t.Errorf("flushed batch = %v, want just d", flushed)
+	}
+
+	cancel()
+	select {
+	case batch, ok := <-batches:
+		if ok {
+			t.Errorf("unexpected batch %v after cancelling", batch)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("batches were not closed after cancelling the context")
+	}
+}
+
+func TestBatchEventsCancelled(t *testing.T) {
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](NewFakeClock(time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC))))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	batches := s.BatchEvents(ctx, 1, time.Second)
+	s.AddReminder(testItem{id: "a", due: time.Now().Add(time.Second)})
+
+	// Nobody takes the batch holding a, which mustn't keep the feed alive
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case _, ok := <-batches:
+		for ok {
+			_, ok = <-batches
+		}
+	case <-time.After(time.Second):
+		t.Fatal("batches were not closed after cancelling ctx")
+	}
+}
+
+func TestHeadBucketStaysSorted(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), 10*time.Second, 4, WithClock[testItem](clock))
+
+	for _, ms := range []int{9000, 100, 15000, 4000, 12000, 2000, -5000, 7000} {
+		s.AddReminder(testItem{id: fmt.Sprint(ms), due: start.Add(time.Duration(ms) * time.Millisecond)})
+	}
+
+	clock.Advance(10 * time.Second)
+	s.Occupancy()
+	for i := 1; i < len(s.buckets[0].elements); i++ {
+		if s.buckets[0].elements[i].due.Before(s.buckets[0].elements[i-1].due) {
+			t.Fatalf("head bucket out of order at %d after rotation", i)
+		}
+	}
+
+	clock.Advance(3 * time.Second)
+	var got []string
+	for _, item := range s.Due() {
+		got = append(got, item.id)
+	}
+	if fmt.Sprint(got) != "[-5000 100 2000 4000 7000 9000 12000]" {
+		t.Errorf("Due() = %v, want [-5000 100 2000 4000 7000 9000 12000]", got)
+	}
+}
+
+func TestNewSchedulerAt(t *testing.T) {
+	base := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(base.Add(1500 * time.Millisecond))
+
+	s, err := NewSchedulerAt[testItem](context.Background(), base, time.Second, 4, WithClock[testItem](clock))
+	if err != nil {
+		t.Fatalf("NewSchedulerAt() error = %v", err)
+	}
+
+	windows := s.Windows()
+	if len(windows) != 4 {
+		t.Fatalf("got %d buckets, want 4", len(windows))
+	}
+	if want := base.Add(time.Second); !windows[0][0].Equal(want) {
+		t.Errorf("head bucket starts at %s, want %s", windows[0][0], want)
+	}
+
+	if _, err := NewSchedulerAt[testItem](context.Background(), time.Time{}, time.Second, 4); err != ErrZeroBase {
+		t.Errorf("NewSchedulerAt(zero) error = %v, want %v", err, ErrZeroBase)
+	}
+}
+
+func TestPage(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	// Insert out of order, spread across every bucket plus both clamps
+	for _, ms := range []int{3900, 100, 2500, 10000, 1200, 3100, -1000, 2100, 1800, 600} {
+		s.AddReminder(testItem{id: fmt.Sprint(ms), due: start.Add(time.Duration(ms) * time.Millisecond)})
+	}
+
+	var got []string
+	for offset := 0; ; offset += 3 {
+		page := s.Page(offset, 3)
+		if len(page) == 0 {
+			break
+		}
+		for _, item := range page {
+			got = append(got, item.id)
+		}
+	}
+
+	want := "[-1000 100 600 1200 1800 2100 2500 3100 3900 10000]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("paged items = %v, want %s", got, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	for _, ms := range []int{3900, 100, 2500, 10000, -1000, 600} {
+		s.AddReminder(testItem{id: fmt.Sprint(ms), due: start.Add(time.Duration(ms) * time.Millisecond)})
+		clock.Advance(700 * time.Millisecond)
+		s.Due()
+		if err := s.Check(); err != nil {
+			t.Fatalf("Check() = %v", err)
+		}
+	}
+
+	s.buckets[2].startTime = s.buckets[2].startTime.Add(time.Millisecond)
+	if err := s.Check(); err == nil {
+		t.Error("Check() = nil for a gap between buckets")
+	}
+}
+
+func TestDueOrder(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		order Order
+		want  string
+	}{
+		{SoonestFirst, "[a b c d]"},
+		{LatestFirst, "[d c b a]"},
+		{InsertionOrder, "[c a d b]"},
+	}
+
+	for _, c := range cases {
+		clock := NewFakeClock(start)
+		s := NewScheduler[testItem](context.Background(), time.Second, 4,
+			WithClock[testItem](clock),
+			WithOrder[testItem](c.order),
+		)
+
+		offsets := map[string]time.Duration{"a": 1, "b": 2, "c": 3, "d": 4}
+		for _, id := range []string{"c", "a", "d", "b"} {
+			s.AddReminder(testItem{id: id, due: start.Add(offsets[id] * 100 * time.Millisecond)})
+		}
+		clock.Advance(time.Second)
+
+		var got []string
+		for _, item := range s.DueLimit(3) {
+			got = append(got, item.id)
+		}
+		for _, item := range s.Due() {
+			got = append(got, item.id)
+		}
+		if fmt.Sprint(got) != c.want {
+			t.Errorf("order %d: got %v, want %s", c.order, got, c.want)
+		}
+	}
+}
+
+func TestDueTieBreakById(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	due := start.Add(1500 * time.Millisecond)
+	for _, id := range []string{"delta", "alpha", "echo", "charlie", "bravo"} {
+		s.AddReminder(testItem{id: id, due: due})
+	}
+	s.AddReminder(testItem{id: "zulu", due: due.Add(-time.Millisecond)})
+
+	clock.Advance(2 * time.Second)
+	var got []string
+	for _, item := range s.Due() {
+		got = append(got, item.id)
+	}
+	if want := "[zulu alpha bravo charlie delta echo]"; fmt.Sprint(got) != want {
+		t.Errorf("Due() = %v, want %s", got, want)
+	}
+}
+
+func TestStrictFIFO(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithStrictFIFO[testItem](),
+	)
+
+	// Spread over every bucket, added in the reverse of due order.
+	for _, id := range []string{"d", "b", "c", "a"} {
+		offset := time.Duration(id[0]-'a') * time.Second
+		s.AddReminder(testItem{id: id, due: start.Add(offset + 500*time.Millisecond)})
+	}
+	clock.Advance(10 * time.Second)
+
+	var got []string
+	for _, item := range s.DueLimit(2) {
+		got = append(got, item.id)
+	}
+	for _, item := range s.Due() {
+		got = append(got, item.id)
+	}
+	if want := "[d b c a]"; fmt.Sprint(got) != want {
+		t.Errorf("got %v, want %s", got, want)
+	}
+}
+
+func TestNextActivity(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	if _, ok := s.NextActivity(); ok {
+		t.Error("NextActivity() ok = true for an empty scheduler")
+	}
+
+	s.AddReminder(testItem{id: "a", due: start.Add(2500 * time.Millisecond)})
+	if next, ok := s.NextActivity(); !ok || !next.Equal(start.Add(time.Second)) {
+		t.Errorf("NextActivity() = %s, %t, want the first bucket boundary", next, ok)
+	}
+
+	clock.Advance(2 * time.Second)
+	if next, ok := s.NextActivity(); !ok || !next.Equal(start.Add(2500*time.Millisecond)) {
+		t.Errorf("NextActivity() = %s, %t, want the due time of a", next, ok)
+	}
+
+	s.Pause()
+	if _, ok := s.NextActivity(); ok {
+		t.Error("NextActivity() ok = true while paused")
+	}
+}
+
+func TestSetResolution(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	for _, ms := range []int{500, 1500, 3500, 6000} {
+		s.AddReminder(testItem{id: fmt.Sprint(ms), due: start.Add(time.Duration(ms) * time.Millisecond)})
+	}
+
+	if err := s.SetResolution(0); err != ErrBlockSize {
+		t.Errorf("SetResolution(0) = %v, want ErrBlockSize", err)
+	}
+	if err := s.SetResolution(2 * time.Second); err != nil {
+		t.Fatalf("SetResolution(2s) = %v", err)
+	}
+	if err := s.Check(); err != nil {
+		t.Fatalf("Check() = %v", err)
+	}
+
+	windows := s.Windows()
+	if len(windows) != 4 || !windows[3][1].Equal(start.Add(8*time.Second)) {
+		t.Errorf("Windows() = %v, want 4 buckets ending at +8s", windows)
+	}
+	if got := fmt.Sprint(s.Occupancy()); got != "[2 1 0 1]" {
+		t.Errorf("Occupancy() = %s, want [2 1 0 1]", got)
+	}
+	if s.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", s.Len())
+	}
+}
+
+func TestShift(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(2500 * time.Millisecond)})
+
+	// Restore the schedule an hour later, as if it had been persisted
+	clock.Set(start.Add(time.Hour))
+	s.Shift(time.Hour)
+	if err := s.Check(); err != nil {
+		t.Fatalf("Check() = %v", err)
+	}
+
+	if got := fmt.Sprint(s.Occupancy()); got != "[1 0 1 0]" {
+		t.Errorf("Occupancy() = %s, want [1 0 1 0]", got)
+	}
+	if next, _ := s.NextDueTime(); !next.Equal(start.Add(time.Hour + 500*time.Millisecond)) {
+		t.Errorf("NextDueTime() = %s, want +1h500ms", next.Sub(start))
+	}
+	if due := s.Due(); len(due) != 0 {
+		t.Errorf("Due() = %v right after shifting, want nothing", due)
+	}
+}
+
+func TestTimeUntilNext(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	if _, ok := s.TimeUntilNext(); ok {
+		t.Error("TimeUntilNext() ok = true for an empty scheduler")
+	}
+
+	s.AddReminder(testItem{id: "b", due: start.Add(3 * time.Second)})
+	s.AddReminder(testItem{id: "a", due: start.Add(2500 * time.Millisecond)})
+
+	if wait, ok := s.TimeUntilNext(); !ok || wait != 2500*time.Millisecond {
+		t.Errorf("TimeUntilNext() = %s, %t, want 2.5s, true", wait, ok)
+	}
+
+	clock.Advance(time.Hour)
+	if wait, ok := s.TimeUntilNext(); !ok || wait != 0 {
+		t.Errorf("TimeUntilNext() = %s, %t for an overdue item, want 0, true", wait, ok)
+	}
+}
+
+type recurringItem struct {
+	testItem
+	interval time.Duration
+	max      int
+}
+
+func (r recurringItem) Interval() time.Duration {
+	return r.interval
+}
+
+func (r recurringItem) MaxOccurrences() int {
+	return r.max
+}
+
+func TestMaxOccurrences(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[recurringItem](context.Background(), time.Second, 10, WithClock[recurringItem](clock))
+	events := s.Events()
+
+	s.AddReminder(recurringItem{testItem{id: "a", due: start.Add(time.Second)}, 2 * time.Second, 3})
+
+	fired := 0
+	for i := 0; i < 20; i++ {
+		clock.Advance(time.Second)
+		fired += len(s.Due())
+	}
+
+	if fired != 3 {
+		t.Errorf("fired %d times, want 3", fired)
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d after the last occurrence, want 0", s.Len())
+	}
+
+	var kinds []string
+	for len(events) > 0 {
+		kinds = append(kinds, (<-events).Kind.String())
+	}
+	if want := "[added fired fired fired completed]"; fmt.Sprint(kinds) != want {
+		t.Errorf("events = %v, want %s", kinds, want)
+	}
+}
+
+func TestCatchUpMode(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		mode  CatchUpMode
+		fired int
+		next  time.Time
+	}{
+		{FireAll, 61, start.Add(61 * time.Minute)},
+		{Skip, 1, start.Add(61 * time.Minute)},
+	}
+
+	for _, c := range cases {
+		clock := NewFakeClock(start)
+		s := NewScheduler[recurringItem](context.Background(), time.Minute, 4,
+			WithClock[recurringItem](clock),
+			WithCatchUpMode[recurringItem](c.mode),
+		)
+		s.AddReminder(recurringItem{testItem{id: "a", due: start}, time.Minute, 0})
+
+		// An hour-long pause, plus a little
+		s.Pause()
+		clock.Advance(time.Hour + 10*time.Second)
+		s.Resume()
+
+		fired := 0
+		for due := s.Due(); len(due) > 0; due = s.Due() {
+			fired += len(due)
+		}
+		if fired != c.fired {
+			t.Errorf("mode %d: fired %d times, want %d", c.mode, fired, c.fired)
+		}
+		if next, _ := s.NextDueTime(); !next.Equal(c.next) {
+			t.Errorf("mode %d: next due at %s, want %s", c.mode, next.Sub(start), c.next.Sub(start))
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[expiringItem](context.Background(), time.Second, 4, WithClock[expiringItem](clock))
+
+	s.AddReminder(expiringItem{testItem{id: "fires", due: start}, time.Time{}})
+	s.AddReminder(expiringItem{testItem{id: "expires", due: start}, start})
+	s.AddReminder(expiringItem{testItem{id: "cancelled", due: start.Add(time.Second)}, time.Time{}})
+	s.AddReminder(expiringItem{testItem{id: "swapped", due: start.Add(time.Second)}, time.Time{}})
+	s.AddReminder(expiringItem{testItem{id: "pending", due: start.Add(2 * time.Second)}, time.Time{}})
+
+	s.Due()
+	s.Cancel("cancelled")
+	s.Swap([]expiringItem{
+		{testItem{id: "pending", due: start.Add(2 * time.Second)}, time.Time{}},
+	})
+
+	checks := []struct {
+		name      string
+		got, want uint64
+	}{
+		{"TotalScheduled", s.TotalScheduled(), 6},
+		{"TotalFired", s.TotalFired(), 1},
+		{"TotalCancelled", s.TotalCancelled(), 3},
+		{"TotalExpired", s.TotalExpired(), 1},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s() = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+	if depth := s.CurrentDepth(); depth != int64(s.Len()) || depth != 1 {
+		t.Errorf("CurrentDepth() = %d, Len() = %d, want 1", depth, s.Len())
+	}
+}
+
+func TestDedupePolicies(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		policy  DedupePolicy
+		err     error
+		wantLen int
+		wantDue time.Time
+	}{
+		{DedupeAllow, nil, 2, start.Add(time.Second)},
+		{DedupeReplace, nil, 1, start.Add(2 * time.Second)},
+		{DedupeReject, ErrDuplicateId, 1, start.Add(time.Second)},
+	}
+
+	for _, c := range cases {
+		clock := NewFakeClock(start)
+		s := NewScheduler[testItem](context.Background(), time.Second, 4,
+			WithClock[testItem](clock),
+			WithDedupe[testItem](c.policy),
+		)
+
+		if s.Has("a") {
+			t.Errorf("policy %d: Has(a) = true before adding it", c.policy)
+		}
+		if err := s.AddReminder(testItem{id: "a", due: start.Add(time.Second)}); err != nil {
+			t.Errorf("policy %d: first AddReminder() = %v", c.policy, err)
+		}
+		if !s.Has("a") {
+			t.Errorf("policy %d: Has(a) = false after adding it", c.policy)
+		}
+		if err := s.AddReminder(testItem{id: "a", due: start.Add(2 * time.Second)}); err != c.err {
+			t.Errorf("policy %d: second AddReminder() = %v, want %v", c.policy, err, c.err)
+		}
+
+		if s.Len() != c.wantLen {
+			t.Errorf("policy %d: Len() = %d, want %d", c.policy, s.Len(), c.wantLen)
+		}
+		if next, _ := s.NextDueTime(); !next.Equal(c.wantDue) {
+			t.Errorf("policy %d: next due %s, want %s", c.policy, next, c.wantDue)
+		}
+	}
+}
+
+// steppingClock moves forward by step every time it is read.
+type steppingClock struct {
+	*FakeClock
+	step time.Duration
+}
+
+func (c steppingClock) Now() time.Time {
+	c.FakeClock.Advance(c.step)
+	return c.FakeClock.Now()
+}
+
+func TestDrainWithin(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Hour, 4, WithClock[testItem](clock))
+
+	for i := 0; i < 3*drainBatch; i++ {
+		s.AddReminder(testItem{id: fmt.Sprint(i), due: start.Add(-time.Minute)})
+	}
+	if got := len(s.DrainWithin(time.Second)); got != 3*drainBatch {
+		t.Errorf("DrainWithin() returned %d items, want %d", got, 3*drainBatch)
+	}
+
+	slow := steppingClock{NewFakeClock(start), time.Millisecond}
+	s = NewScheduler[testItem](context.Background(), time.Hour, 4, WithClock[testItem](slow))
+	for i := 0; i < 3*drainBatch; i++ {
+		s.AddReminder(testItem{id: fmt.Sprint(i), due: start.Add(-time.Minute)})
+	}
+	if got := len(s.DrainWithin(time.Nanosecond)); got != drainBatch {
+		t.Errorf("DrainWithin() past its budget returned %d items, want one batch of %d", got, drainBatch)
+	}
+}
+
+func TestRange(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](NewFakeClock(start)))
+
+	if _, _, ok := s.Range(); ok {
+		t.Error("Range() ok = true for an empty scheduler")
+	}
+
+	for _, offset := range []time.Duration{time.Second, -time.Hour, 24 * time.Hour, 2 * time.Second} {
+		s.AddReminder(testItem{id: "x", due: start.Add(offset)})
+	}
+
+	earliest, latest, ok := s.Range()
+	if !ok || !earliest.Equal(start.Add(-time.Hour)) || !latest.Equal(start.Add(24*time.Hour)) {
+		t.Errorf("Range() = %s, %s, %t", earliest, latest, ok)
+	}
+}
+
+func TestTimeUntil(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(3 * time.Second)})
+
+	if d, ok := s.TimeUntil("a"); !ok || d != 3*time.Second {
+		t.Errorf("TimeUntil(a) = %s, %t, want 3s, true", d, ok)
+	}
+
+	clock.Advance(5 * time.Second)
+	if d, ok := s.TimeUntil("a"); !ok || d != -2*time.Second {
+		t.Errorf("TimeUntil(a) = %s, %t for an overdue item, want -2s, true", d, ok)
+	}
+
+	if _, ok := s.TimeUntil("missing"); ok {
+		t.Error("TimeUntil(missing) ok = true")
+	}
+}
+
+func TestOnBucketActive(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	type activation struct {
+		start time.Time
+		size  int
+	}
+	var activations []activation
+	var s *Scheduler[testItem]
+	s = NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithOnBucketActive[testItem](func(start, end time.Time, size int) {
+			// Calling back into the scheduler would deadlock if the lock were held
+			s.Len()
+			activations = append(activations, activation{start, size})
+		}),
+	)
+
+	s.AddReminder(testItem{id: "a", due: start.Add(1500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(1700 * time.Millisecond)})
+
+	clock.Advance(time.Second)
+	s.Occupancy()
+	s.Occupancy()
+	clock.Advance(3 * time.Second)
+	s.Occupancy()
+
+	want := []activation{{start.Add(time.Second), 2}, {start.Add(4 * time.Second), 2}}
+	if fmt.Sprint(activations) != fmt.Sprint(want) {
+		t.Errorf("activations = %v, want %v", activations, want)
+	}
+}
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Log(level, msg string, kv ...any) {
+	l.messages = append(l.messages, level+": "+msg)
+}
+
+func TestLogger(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	logger := &recordingLogger{}
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithLogger[testItem](logger),
+	)
+
+	s.AddReminder(testItem{id: "a", due: start.Add(-time.Hour)})
+	s.AddReminder(testItem{id: "b", due: start.Add(time.Hour)})
+	clock.Advance(time.Second)
+	s.Due()
+
+	want := []string{
+		"debug: clamped overdue item into head bucket",
+		"debug: item due beyond horizon",
+		"debug: item due beyond horizon",
+		"debug: rotated buckets",
+	}
+	if fmt.Sprint(logger.messages) != fmt.Sprint(want) {
+		t.Errorf("logged %q, want %q", logger.messages, want)
+	}
+}
+
+type kvLogger struct {
+	kvs [][]any
+}
+
+func (l *kvLogger) Log(level, msg string, kv ...any) {
+	l.kvs = append(l.kvs, kv)
+}
+
+func TestWithName(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	logger := &kvLogger{}
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithName[testItem]("billing"),
+		WithLogger[testItem](logger),
+		WithClock[testItem](clock),
+	)
+
+	s.AddReminder(testItem{id: "a", due: start.Add(-time.Hour)})
+	if len(logger.kvs) == 0 || len(logger.kvs[0]) < 2 || logger.kvs[0][0] != "scheduler" || logger.kvs[0][1] != "billing" {
+		t.Errorf("logged %v, want the scheduler name first", logger.kvs)
+	}
+
+	want := `Scheduler "billing": 4 x 1s buckets from 2022-09-22 11:00:00 +0000 UTC, 1 items`
+	if got := s.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	unnamed := NewScheduler[testItem](context.Background(), time.Second, 2, WithClock[testItem](clock))
+	if got := unnamed.String(); !strings.HasPrefix(got, "Scheduler: ") {
+		t.Errorf("String() = %q for an unnamed scheduler", got)
+	}
+}
+
+func TestReserve(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(1500 * time.Millisecond)})
+	s.Reserve(64)
+
+	for i, bucket := range s.buckets {
+		if cap(bucket.elements) < 64 {
+			t.Errorf("bucket %d has capacity %d, want at least 64", i, cap(bucket.elements))
+		}
+	}
+	if s.Len() != 1 || !s.Has("a") {
+		t.Error("Reserve changed the scheduled items")
+	}
+
+	clock.Advance(time.Second)
+	s.Occupancy()
+	if last := s.buckets[len(s.buckets)-1]; cap(last.elements) < 64 {
+		t.Errorf("new bucket has capacity %d, want at least 64", cap(last.elements))
+	}
+}
+
+type namespacedItem struct {
+	testItem
+	namespace string
+}
+
+func TestDedupeKeepEarliestLatest(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		policy  DedupePolicy
+		wantDue time.Time
+		kept    []bool
+	}{
+		{DedupeKeepEarliest, start.Add(time.Second), []bool{true, true, false}},
+		{DedupeKeepLatest, start.Add(3 * time.Second), []bool{true, false, true}},
+	}
+
+	for _, c := range cases {
+		clock := NewFakeClock(start)
+		s := NewScheduler[testItem](context.Background(), time.Second, 4,
+			WithClock[testItem](clock),
+			WithDedupe[testItem](c.policy),
+		)
+
+		for i, offset := range []time.Duration{2, 1, 3} {
+			idx := s.AddReminderAt(testItem{id: "a", due: start.Add(offset * time.Second)})
+			if kept := idx != Rejected; kept != c.kept[i] {
+				t.Errorf("policy %d: insert at +%ds kept = %t, want %t", c.policy, offset, kept, c.kept[i])
+			}
+		}
+
+		if s.Len() != 1 {
+			t.Errorf("policy %d: Len() = %d, want 1", c.policy, s.Len())
+		}
+		if next, _ := s.NextDueTime(); !next.Equal(c.wantDue) {
+			t.Errorf("policy %d: next due %s, want %s", c.policy, next.Sub(start), c.wantDue.Sub(start))
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithDedupe[testItem](DedupeReject),
+		WithCapacity[testItem](2),
+	)
+
+	s.AddReminder(testItem{id: "a", due: start.Add(time.Second)})
+
+	cases := []struct {
+		item testItem
+		want error
+	}{
+		{testItem{id: "b", due: start.Add(time.Hour)}, nil},
+		{testItem{id: "b", due: start.Add(-time.Hour)}, nil},
+		{testItem{id: "b"}, ErrZeroDue},
+		{testItem{id: "a", due: start}, ErrDuplicateId},
+	}
+	for _, c := range cases {
+		if err := s.Validate(c.item); err != c.want {
+			t.Errorf("Validate(%+v) = %v, want %v", c.item, err, c.want)
+		}
+		if err := s.Clone().AddReminder(c.item); err != c.want {
+			t.Errorf("AddReminder(%+v) = %v, want the same as Validate", c.item, err)
+		}
+	}
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d after validating, want 1", s.Len())
+	}
+
+	s.AddReminder(testItem{id: "b", due: start})
+	if err := s.Validate(testItem{id: "c", due: start}); err != ErrCapacity {
+		t.Errorf("Validate() at capacity = %v, want ErrCapacity", err)
+	}
+}
+
+func TestDedupeKey(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	s := NewScheduler[namespacedItem](context.Background(), time.Second, 4,
+		WithClock[namespacedItem](NewFakeClock(start)),
+		WithDedupe[namespacedItem](DedupeReject),
+		WithDedupeKey(func(item namespacedItem) string {
+			return item.namespace + "/" + item.id
+		}),
+	)
+
+	if err := s.AddReminder(namespacedItem{testItem{id: "a", due: start}, "users"}); err != nil {
+		t.Fatalf("AddReminder(users/a) = %v", err)
+	}
+	if err := s.AddReminder(namespacedItem{testItem{id: "a", due: start}, "groups"}); err != nil {
+		t.Errorf("AddReminder(groups/a) = %v, want nil since the key differs", err)
+	}
+	if err := s.AddReminder(namespacedItem{testItem{id: "a", due: start}, "users"}); err != ErrDuplicateId {
+		t.Errorf("AddReminder(users/a) again = %v, want %v", err, ErrDuplicateId)
+	}
+
+	if !s.Has("users/a") || s.Has("a") {
+		t.Error("Has() should look items up by dedupe key")
+	}
+
+	// Cancel works on Id(), so it removes the item from both namespaces
+	if !s.Cancel("a") || s.Len() != 0 {
+		t.Errorf("Cancel(a) left %d items", s.Len())
+	}
+}
+
+func TestRemoveWhere(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](NewFakeClock(start)))
+
+	for i := 0; i < 100; i++ {
+		s.AddReminder(testItem{id: fmt.Sprint(i), due: start.Add(time.Duration(i) * time.Millisecond)})
+	}
+
+	removed := s.RemoveWhere(func(item testItem) bool {
+		var n int
+		fmt.Sscan(item.id, &n)
+		return n%2 == 1
+	})
+
+	if len(removed) != 50 {
+		t.Fatalf("RemoveWhere() removed %d items, want 50", len(removed))
+	}
+	for i, item := range removed {
+		if want := fmt.Sprint(2*i + 1); item.id != want {
+			t.Fatalf("removed[%d] = %s, want %s", i, item.id, want)
+		}
+	}
+
+	remaining := s.Page(0, 100)
+	if len(remaining) != 50 {
+		t.Fatalf("%d items remain, want 50", len(remaining))
+	}
+	for i, item := range remaining {
+		if want := fmt.Sprint(2 * i); item.id != want {
+			t.Fatalf("remaining[%d] = %s, want %s", i, item.id, want)
+		}
+	}
+	if s.TotalCancelled() != 50 {
+		t.Errorf("TotalCancelled() = %d, want 50", s.TotalCancelled())
+	}
+}
+
+func TestDueAsOf(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(2500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "c", due: start.Add(10 * time.Second)})
+
+	// The clock never moves; the replayed timestamps drive everything
+	if due := s.DueAsOf(start.Add(time.Second)); len(due) != 1 || due[0].id != "a" {
+		t.Errorf("DueAsOf(+1s) = %v, want [a]", due)
+	}
+	if due := s.DueAsOf(start.Add(2 * time.Second)); len(due) != 0 {
+		t.Errorf("DueAsOf(+2s) = %v, want nothing", due)
+	}
+	if due := s.DueAsOf(start.Add(11 * time.Second)); len(due) != 2 || due[0].id != "b" || due[1].id != "c" {
+		t.Errorf("DueAsOf(+11s) = %v, want [b c]", due)
+	}
+	if !clock.Now().Equal(start) {
+		t.Errorf("clock moved to %s", clock.Now())
+	}
+
+	s.AddReminder(testItem{id: "d", due: start.Add(12 * time.Second)})
+	if due := s.Due(); len(due) != 0 {
+		t.Errorf("Due() by the clock = %v, want nothing", due)
+	}
+}
+
+func TestIds(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock), WithDedupe[testItem](DedupeAllow))
+
+	s.AddReminder(testItem{id: "c", due: start.Add(3 * time.Second)})
+	s.AddReminder(testItem{id: "b", due: start.Add(time.Second)})
+	s.AddReminder(testItem{id: "a", due: start.Add(time.Second)})
+	s.AddReminder(testItem{id: "c", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "d", due: start.Add(time.Hour)})
+
+	if got, want := s.Ids(), []string{"c", "a", "b", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ids() = %v, want %v", got, want)
+	}
+}
+
+func TestTimeSinceLastUpdate(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	clock.Advance(3 * time.Second)
+	if d := s.TimeSinceLastUpdate(); d != 3*time.Second {
+		t.Errorf("TimeSinceLastUpdate() after creation = %s, want 3s", d)
+	}
+
+	s.Due()
+	clock.Advance(1500 * time.Millisecond)
+	if d := s.TimeSinceLastUpdate(); d != 1500*time.Millisecond {
+		t.Errorf("TimeSinceLastUpdate() after Due = %s, want 1.5s", d)
+	}
+
+	// Asking doesn't count as an update
+	clock.Advance(time.Second)
+	if d := s.TimeSinceLastUpdate(); d != 2500*time.Millisecond {
+		t.Errorf("TimeSinceLastUpdate() = %s, want 2.5s", d)
+	}
+}
+
+func TestMaxFuture(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock), WithMaxFuture[testItem](time.Hour))
+
+	if err := s.AddReminder(testItem{id: "a", due: start.Add(time.Hour)}); err != nil {
+		t.Errorf("AddReminder at the limit = %v, want nil", err)
+	}
+	if err := s.AddReminder(testItem{id: "b", due: start.Add(time.Hour + 1)}); err != ErrTooFar {
+		t.Errorf("AddReminder past the limit = %v, want ErrTooFar", err)
+	}
+	if err := s.AddReminder(testItem{id: "c", due: time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)}); err != ErrTooFar {
+		t.Errorf("AddReminder in the year 3000 = %v, want ErrTooFar", err)
+	}
+
+	// The limit moves with the clock
+	clock.Advance(time.Minute)
+	if err := s.AddReminder(testItem{id: "b", due: start.Add(time.Hour + 1)}); err != nil {
+		t.Errorf("AddReminder a minute later = %v, want nil", err)
+	}
+	if n := s.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+}
+
+func TestDueGrid(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Minute, 4,
+		WithClock[testItem](clock), WithDueGrid[testItem](5*time.Second))
+
+	for i, c := range []struct {
+		offset  time.Duration
+		snapped bool
+	}{
+		{5001 * time.Millisecond, true},
+		{7 * time.Second, true},
+		{10 * time.Second, false},
+	} {
+		if _, snapped := s.AddReminderSnapped(testItem{id: fmt.Sprintf("%d", i), due: start.Add(c.offset)}); snapped != c.snapped {
+			t.Errorf("AddReminderSnapped(+%s) snapped = %v, want %v", c.offset, snapped, c.snapped)
+		}
+	}
+	s.AddReminder(testItem{id: "next cell", due: start.Add(10*time.Second + 1)})
+
+	for _, id := range []string{"0", "1", "2"} {
+		if d, _ := s.TimeUntil(id); d != 10*time.Second {
+			t.Errorf("%s is due in %s, want 10s on the grid", id, d)
+		}
+	}
+	if d, _ := s.TimeUntil("next cell"); d != 15*time.Second {
+		t.Errorf("next cell is due in %s, want 15s", d)
+	}
+
+	clock.Advance(10 * time.Second)
+	if due := s.Due(); len(due) != 3 {
+		t.Errorf("Due() at the grid point = %v, want all three together", due)
+	}
+}
+
+func TestFireNow(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[recurringItem](context.Background(), time.Second, 4, WithClock[recurringItem](clock))
+	events := s.Events()
+
+	s.AddReminder(recurringItem{testItem: testItem{id: "once", due: start.Add(3 * time.Second)}})
+	s.AddReminder(recurringItem{testItem: testItem{id: "every", due: start.Add(2 * time.Second)}, interval: time.Minute})
+	<-events
+	<-events
+
+	item, ok := s.FireNow("once")
+	if !ok || item.id != "once" {
+		t.Fatalf("FireNow(once) = %v, %v", item, ok)
+	}
+	if e := <-events; e.Kind != Fired || e.Item.id != "once" {
+		t.Errorf("event = %v %s, want fired once", e.Kind, e.Item.id)
+	}
+	if _, ok := s.FireNow("once"); ok {
+		t.Error("FireNow(once) = true once it had already fired")
+	}
+
+	if _, ok := s.FireNow("every"); !ok {
+		t.Fatal("FireNow(every) = false")
+	}
+	if d, ok := s.TimeUntil("every"); !ok || d != time.Minute+2*time.Second {
+		t.Errorf("recurring item next due in %s, %v, want its following occurrence in 1m2s", d, ok)
+	}
+	if n := s.TotalFired(); n != 2 {
+		t.Errorf("Fired = %d, want 2", n)
+	}
+}
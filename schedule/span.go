@@ -0,0 +1,122 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+)
+
+// Spanning is implemented by items that represent an interval rather than an
+// instant. They are scheduled and fired at their DueTime like any other item,
+// and are also tracked until EndTime so ActiveAt can report what is in
+// progress. An EndTime that isn't after DueTime makes the item a plain,
+// instantaneous one.
+type Spanning interface {
+	EndTime() time.Time
+}
+
+// span is one occurrence of a Spanning item.
+type span[T Schedulable] struct {
+	item       T
+	seq        uint64
+	start, end time.Time
+}
+
+// ActiveAt returns every Spanning item whose [DueTime, EndTime) contains t,
+// whether it has fired yet or not, ordered by start. Occurrences are
+// forgotten once they have ended, so t should be now or in the future.
+func (s *Scheduler[T]) ActiveAt(t time.Time) []T {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	active := make([]span[T], 0)
+	for _, sp := range s.spans {
+		if !sp.start.After(t) && sp.end.After(t) {
+			active = append(active, sp)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].start.Before(active[j].start)
+	})
+
+	items := make([]T, 0, len(active))
+	for _, sp := range active {
+		items = append(items, sp.item)
+	}
+	return items
+}
+
+// SpanCount returns how many buckets the next occurrence of the item with
+// the given id overlaps, counting the one it is due in, with false if no such
+// item is scheduled. Items that aren't Spanning always span one bucket.
+func (s *Scheduler[T]) SpanCount(id string) (int, bool) {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	entity := s.find(id)
+	if entity == nil {
+		return 0, false
+	}
+	if entity.length <= 0 {
+		return 1, true
+	}
+
+	first := s.windowOf(entity.due)[0]
+	covered := entity.due.Add(entity.length).Sub(first)
+	n := int(covered / s.blockSize)
+	if covered%s.blockSize != 0 {
+		n++
+	}
+	return n, true
+}
+
+// track starts tracking the occurrence of entity that is due next.
+func (s *Scheduler[T]) track(entity *entry[T]) {
+	if entity.length > 0 {
+		s.spans = append(s.spans, span[T]{
+			item:  entity.item,
+			seq:   entity.seq,
+			start: entity.due,
+			end:   entity.due.Add(entity.length),
+		})
+	}
+}
+
+// retrack moves the tracked occurrence of entity that was due at from to its
+// new due time.
+func (s *Scheduler[T]) retrack(entity *entry[T], from time.Time) {
+	if entity.length <= 0 {
+		return
+	}
+	for i, sp := range s.spans {
+		if sp.seq == entity.seq && sp.start.Equal(from) {
+			s.spans[i].start = entity.due
+			s.spans[i].end = entity.due.Add(entity.length)
+			return
+		}
+	}
+}
+
+// untrack forgets every occurrence of an entity that has been removed.
+func (s *Scheduler[T]) untrack(entity *entry[T]) {
+	if entity.length > 0 {
+		s.pruneSpans(func(sp span[T]) bool {
+			return sp.seq == entity.seq
+		})
+	}
+}
+
+// pruneSpans forgets the occurrences matching pred.
+func (s *Scheduler[T]) pruneSpans(pred func(span[T]) bool) {
+	kept := s.spans[:0]
+	for _, sp := range s.spans {
+		if !pred(sp) {
+			kept = append(kept, sp)
+		}
+	}
+	for i := len(kept); i < len(s.spans); i++ {
+		s.spans[i] = span[T]{}
+	}
+	s.spans = kept
+}
@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type spanningItem struct {
+	testItem
+	end time.Time
+}
+
+func (s spanningItem) EndTime() time.Time {
+	return s.end
+}
+
+func activeIds(items []spanningItem) string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.id)
+	}
+	return fmt.Sprint(ids)
+}
+
+func TestActiveAt(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[spanningItem](context.Background(), time.Second, 4, WithClock[spanningItem](clock))
+
+	s.AddReminder(spanningItem{testItem{id: "long", due: start.Add(500 * time.Millisecond)}, start.Add(3 * time.Second)})
+	s.AddReminder(spanningItem{testItem{id: "short", due: start.Add(1500 * time.Millisecond)}, start.Add(2 * time.Second)})
+	s.AddReminder(spanningItem{testItem{id: "instant", due: start.Add(1500 * time.Millisecond)}, time.Time{}})
+	s.AddReminder(spanningItem{testItem{id: "cancelled", due: start.Add(time.Second)}, start.Add(3 * time.Second)})
+	s.Cancel("cancelled")
+
+	if got := activeIds(s.ActiveAt(start.Add(1700 * time.Millisecond))); got != "[long short]" {
+		t.Errorf("ActiveAt(+1.7s) = %s, want [long short]", got)
+	}
+
+	// Spanning items fire at their start but stay active until they end
+	clock.Advance(time.Second)
+	if due := s.Due(); len(due) != 1 || due[0].id != "long" {
+		t.Errorf("Due() = %v, want [long]", due)
+	}
+	if got := activeIds(s.ActiveAt(clock.Now())); got != "[long]" {
+		t.Errorf("ActiveAt(now) = %s after long fired, want [long]", got)
+	}
+
+	clock.Advance(2 * time.Second)
+	s.Due()
+	if got := activeIds(s.ActiveAt(clock.Now())); got != "[]" {
+		t.Errorf("ActiveAt(now) = %s once every span has ended, want []", got)
+	}
+	if len(s.spans) != 0 {
+		t.Errorf("still tracking %d spans after they ended", len(s.spans))
+	}
+}
+
+func TestSpanCount(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	s := NewScheduler[spanningItem](context.Background(), time.Second, 4,
+		WithClock[spanningItem](NewFakeClock(start)))
+
+	s.AddReminder(spanningItem{testItem{id: "long", due: start.Add(500 * time.Millisecond)}, start.Add(3 * time.Second)})
+	s.AddReminder(spanningItem{testItem{id: "exact", due: start.Add(time.Second)}, start.Add(3 * time.Second)})
+	s.AddReminder(spanningItem{testItem{id: "short", due: start.Add(1200 * time.Millisecond)}, start.Add(1800 * time.Millisecond)})
+	s.AddReminder(spanningItem{testItem{id: "instant", due: start.Add(1500 * time.Millisecond)}, time.Time{}})
+
+	for id, want := range map[string]int{"long": 3, "exact": 2, "short": 1, "instant": 1} {
+		if n, ok := s.SpanCount(id); !ok || n != want {
+			t.Errorf("SpanCount(%s) = %d, %v, want %d, true", id, n, ok, want)
+		}
+	}
+	if n, ok := s.SpanCount("missing"); ok || n != 0 {
+		t.Errorf("SpanCount(missing) = %d, %v, want 0, false", n, ok)
+	}
+}
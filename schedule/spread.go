@@ -0,0 +1,55 @@
+package schedule
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// WithDeterministicSpread spreads recurring items over their interval so that
+// items which come due together don't keep coming due together. Each item's
+// next occurrence is moved to a fixed offset within its interval, derived
+// from a hash of its Id, and it then recurs at that offset every interval.
+// The same Id always gets the same offset, so the schedule is reproducible.
+// The first re-scheduled occurrence may come up to one interval early or late
+// as it moves onto its offset.
+func WithDeterministicSpread[T Schedulable]() Option[T] {
+	return func(s *Scheduler[T]) {
+		s.spreadRecurring = true
+	}
+}
+
+// nextOccurrence returns when a recurring entry that has just fired is next
+// due.
+func (s *Scheduler[T]) nextOccurrence(entity *entry[T]) time.Time {
+	if entity.weekly != nil {
+		return s.nextWeekly(entity.weekly, entity.due)
+	}
+
+	next := entity.due.Add(entity.interval)
+	if s.spreadRecurring {
+		next = next.Truncate(entity.interval).Add(spreadOffset(entity.id, entity.interval))
+	}
+
+	if now := s.now(); s.catchUp == Skip && !next.After(now) {
+		missed := now.Sub(next)/entity.interval + 1
+		next = next.Add(missed * entity.interval)
+	}
+	return next
+}
+
+// nextWeekly returns when a weekly reminder last due at due is next due. It
+// isn't spread, since it is meant to come due at a particular time of day.
+func (s *Scheduler[T]) nextWeekly(weekly *WeeklyAt, due time.Time) time.Time {
+	next := weekly.Next(due)
+	if now := s.now(); s.catchUp == Skip && !next.After(now) {
+		next = weekly.Next(now.In(due.Location()))
+	}
+	return next
+}
+
+// spreadOffset returns the stable offset within interval for id.
+func spreadOffset(id string, interval time.Duration) time.Duration {
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return time.Duration(h.Sum64() % uint64(interval))
+}
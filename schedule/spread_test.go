@@ -0,0 +1,61 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDeterministicSpread(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	interval := 10 * time.Second
+	ids := []string{"a", "b", "c", "d", "e"}
+
+	// offsets fires every item twice and returns where each landed within
+	// its interval after each occurrence
+	offsets := func() map[string][2]time.Duration {
+		clock := NewFakeClock(start)
+		s := NewScheduler[recurringItem](context.Background(), time.Second, 30,
+			WithClock[recurringItem](clock),
+			WithDeterministicSpread[recurringItem](),
+		)
+		for _, id := range ids {
+			s.AddReminder(recurringItem{testItem{id: id, due: start}, interval, 0})
+		}
+
+		got := make(map[string][2]time.Duration)
+		for occurrence := 0; occurrence < 2; occurrence++ {
+			fired := 0
+			for fired < len(ids) {
+				fired += len(s.Due())
+				clock.Advance(100 * time.Millisecond)
+			}
+			for _, id := range ids {
+				wait, _ := s.TimeUntil(id)
+				due := clock.Now().Add(wait)
+				offset := got[id]
+				offset[occurrence] = due.Sub(due.Truncate(interval))
+				got[id] = offset
+			}
+		}
+		return got
+	}
+
+	first, second := offsets(), offsets()
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Errorf("offsets differ between runs: %v and %v", first, second)
+	}
+
+	seen := make(map[time.Duration]bool)
+	for _, id := range ids {
+		offset := first[id]
+		if offset[0] != offset[1] || offset[0] != spreadOffset(id, interval) {
+			t.Errorf("%s landed at %v, want %s both times", id, offset, spreadOffset(id, interval))
+		}
+		seen[offset[0]] = true
+	}
+	if len(seen) == 1 {
+		t.Errorf("every item landed at the same offset %v", first)
+	}
+}
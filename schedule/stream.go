@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+// StreamDue sends each item on the returned channel as it comes due, timed by
+// the scheduler's clock, removing it as Due would, until until; then it
+// closes the channel. Items due after until stay scheduled. The stream also
+// closes when ctx is cancelled, and any item that had been taken but not yet
+// received is passed to WithDeadLetter as Abandoned.
+func (s *Scheduler[T]) StreamDue(ctx context.Context, until time.Time) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+
+		var timer Timer
+		for {
+			due, wait, done := s.streamStep(until)
+			for i, entity := range due {
+				select {
+				case out <- entity.item:
+				case <-ctx.Done():
+					s.abandon(due[i:])
+					return
+				}
+			}
+			if done {
+				return
+			}
+
+			if timer == nil {
+				timer = s.clock.NewTimer(wait)
+				defer timer.Stop()
+			} else {
+				timer.Reset(wait)
+			}
+			select {
+			case <-timer.C():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// streamStep takes everything due by now, or by until if that has passed,
+// and returns it along with how long to wait before looking again and whether
+// the stream is over. The wait is capped at blockSize so that items added in
+// the meantime aren't missed.
+func (s *Scheduler[T]) streamStep(until time.Time) ([]*entry[T], time.Duration, bool) {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	now := s.now()
+	if now.After(until) {
+		// Only hand out what was due by the end of the window
+		s.asOf = until
+		defer func() { s.asOf = time.Time{} }()
+	}
+	due := s.takeDue(-1)
+	if !now.Before(until) {
+		return due, 0, true
+	}
+
+	wait := until.Sub(now)
+	if s.blockSize < wait {
+		wait = s.blockSize
+	}
+	if next, ok := s.nextDue(); ok && next.After(now) && next.Sub(now) < wait {
+		wait = next.Sub(now)
+	}
+	return due, wait, false
+}
@@ -0,0 +1,81 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStreamDue(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Minute, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(10 * time.Second)})
+	s.AddReminder(testItem{id: "b", due: start.Add(25 * time.Second)})
+	s.AddReminder(testItem{id: "late", due: start.Add(45 * time.Second)})
+
+	stream := s.StreamDue(context.Background(), start.Add(30*time.Second))
+
+	// Each item arrives exactly when it is due, not at the end of its bucket
+	for _, want := range []string{"a", "b"} {
+		clock.BlockUntil(1)
+		next, _ := s.NextDueTime()
+		clock.Set(next)
+		select {
+		case item := <-stream:
+			if item.id != want || !clock.Now().Equal(item.due) {
+				t.Errorf("received %s at %s, want %s when it was due", item.id, clock.Now().Format("15:04:05"), want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+
+	// Jumping past the end of the window still leaves later items alone
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	select {
+	case item, ok := <-stream:
+		if ok {
+			t.Errorf("received %s after the window ended", item.id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream was not closed at the end of the window")
+	}
+	if n := s.Len(); n != 1 {
+		t.Errorf("Len() = %d, want late still scheduled", n)
+	}
+}
+
+func TestStreamDueCancel(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	abandoned := make(chan string, 1)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithDeadLetter(func(item testItem, reason Reason) {
+			if reason == Abandoned {
+				abandoned <- item.id
+			}
+		}),
+	)
+	s.AddReminder(testItem{id: "a", due: start.Add(-time.Second)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := s.StreamDue(ctx, start.Add(time.Hour))
+	cancel()
+
+	select {
+	case id := <-abandoned:
+		if id != "a" {
+			t.Errorf("abandoned %s, want a", id)
+		}
+	case <-stream:
+		// Received before the cancellation was noticed
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a")
+	}
+	for range stream {
+	}
+}
@@ -0,0 +1,196 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// Summary is a point-in-time overview of a scheduler, gathered under a single
+// acquisition of its lock.
+type Summary struct {
+	Items        int
+	Overdue      int
+	Buckets      int
+	Covered      time.Duration
+	Earliest     time.Time
+	Latest       time.Time
+	MaxOccupancy int
+}
+
+// Summary returns an overview of the scheduler's contents and layout.
+// Earliest and Latest are zero when nothing is scheduled.
+func (s *Scheduler[T]) Summary() Summary {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	summary := Summary{
+		Items:   s.size(),
+		Overdue: s.overdueCount(),
+		Buckets: len(s.buckets),
+		Covered: s.buckets[len(s.buckets)-1].endTime.Sub(s.buckets[0].startTime),
+	}
+
+	for _, bucket := range s.buckets {
+		if len(bucket.elements) > summary.MaxOccupancy {
+			summary.MaxOccupancy = len(bucket.elements)
+		}
+	}
+
+	first := true
+	for _, entity := range s.entries() {
+		if first || entity.due.Before(summary.Earliest) {
+			summary.Earliest = entity.due
+		}
+		if first || entity.due.After(summary.Latest) {
+			summary.Latest = entity.due
+		}
+		first = false
+	}
+
+	return summary
+}
+
+// OverdueCount returns the number of items that are overdue, meaning they
+// were due before the head bucket's window began.
+func (s *Scheduler[T]) OverdueCount() int {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	return s.overdueCount()
+}
+
+// WouldBeOverdue reports whether an item due at due would be overdue if it
+// were added now, so a caller can handle it straight away instead of
+// scheduling it only to drain it again.
+func (s *Scheduler[T]) WouldBeOverdue(due time.Time) bool {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	return due.Before(s.buckets[0].startTime)
+}
+
+// InCurrentWindow reports whether t falls within the head bucket's
+// [start, end) window, so an item due then would be handed out by the very
+// next Due. Together with WouldBeOverdue it sorts a due time into past, now
+// and future.
+func (s *Scheduler[T]) InCurrentWindow(t time.Time) bool {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	head := s.buckets[0]
+	return !t.Before(head.startTime) && t.Before(head.endTime)
+}
+
+// EstimateFireTime returns roughly when an item due at due would be
+// delivered by the loop started by Start if it were added now: now if it is
+// already due; with WithFlushAlignment, the first boundary at or after due;
+// with WithPreciseTimers, due itself; with WithTickInterval, one tick after
+// due at the latest; and otherwise the end of the bucket window it falls in.
+// That last is only an approximation, since the loop checks every blockSize
+// from whenever it was started rather than on bucket boundaries, so it may
+// deliver the item up to one blockSize either side of the estimate.
+func (s *Scheduler[T]) EstimateFireTime(due time.Time) time.Time {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	if now := s.now(); !due.After(now) {
+		return now
+	}
+	if d := s.flushAlignment; d > 0 {
+		boundary := due.Truncate(d)
+		if boundary.Before(due) {
+			boundary = boundary.Add(d)
+		}
+		return boundary
+	}
+	if s.precise {
+		return due
+	}
+	if s.tickInterval > 0 {
+		return due.Add(s.tickInterval)
+	}
+	return s.windowOf(due)[1]
+}
+
+// explainLayout is how ExplainPlacement formats times.
+const explainLayout = "2006-01-02 15:04:05.000"
+
+// ExplainPlacement describes, for debugging, where an item due at due would
+// be placed if it were added now and why, e.g. that it is before the head
+// bucket and would be clamped into it as overdue. Nothing is added.
+func (s *Scheduler[T]) ExplainPlacement(due time.Time) string {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	head, tail := s.buckets[0], s.buckets[len(s.buckets)-1]
+	result, idx := s.locate(due)
+	at := due.Format(explainLayout)
+
+	switch result {
+	case ClampedHead:
+		return fmt.Sprintf("due time %s is before head start %s, would clamp to head (overdue)", at, head.startTime.Format(explainLayout))
+	case ClampedTail:
+		if s.overflow != nil {
+			return fmt.Sprintf("due time %s is at or after horizon end %s, would wait in overflow", at, tail.endTime.Format(explainLayout))
+		}
+		return fmt.Sprintf("due time %s is at or after horizon end %s, would clamp to tail bucket %d", at, tail.endTime.Format(explainLayout), idx)
+	}
+
+	bucket := s.buckets[idx]
+	return fmt.Sprintf("due time %s is within bucket %d [%s, %s)", at, idx, bucket.startTime.Format(explainLayout), bucket.endTime.Format(explainLayout))
+}
+
+// DueHistogram divides the horizon, from the start of the head bucket to the
+// end of the last, into n equal slices and returns how many pending items are
+// due in each. Overdue items are counted in the first slice and items beyond
+// the horizon in the last, as they are in the buckets themselves. It returns
+// nil if n is not positive.
+func (s *Scheduler[T]) DueHistogram(n int) []int {
+	if n <= 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	start := s.buckets[0].startTime
+	width := s.buckets[len(s.buckets)-1].endTime.Sub(start)
+
+	counts := make([]int, n)
+	for _, bucket := range s.stores() {
+		for _, entity := range bucket.elements {
+			offset := entity.due.Sub(start)
+			switch {
+			case offset <= 0:
+				counts[0]++
+			case offset >= width:
+				counts[n-1]++
+			default:
+				counts[int(offset*time.Duration(n)/width)]++
+			}
+		}
+	}
+	return counts
+}
+
+func (s *Scheduler[T]) overdueCount() int {
+	// Overdue items always end up in the head bucket, which is sorted
+	head := s.buckets[0]
+	count := 0
+	for _, entity := range head.elements {
+		if !entity.due.Before(head.startTime) {
+			break
+		}
+		if !entity.held {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,179 @@
+package schedule
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSummary(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	if summary := s.Summary(); summary.Items != 0 || !summary.Earliest.IsZero() || summary.Covered != 4*time.Second {
+		t.Errorf("empty Summary() = %+v", summary)
+	}
+
+	s.AddReminder(testItem{id: "a", due: start.Add(-time.Minute)})
+	s.AddReminder(testItem{id: "b", due: start.Add(-time.Second)})
+	s.AddReminder(testItem{id: "c", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "d", due: start.Add(2500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "e", due: start.Add(time.Hour)})
+
+	want := Summary{
+		Items:        5,
+		Overdue:      2,
+		Buckets:      4,
+		Covered:      4 * time.Second,
+		Earliest:     start.Add(-time.Minute),
+		Latest:       start.Add(time.Hour),
+		MaxOccupancy: 3,
+	}
+	if summary := s.Summary(); summary != want {
+		t.Errorf("Summary() = %+v, want %+v", summary, want)
+	}
+	if n := s.OverdueCount(); n != 2 {
+		t.Errorf("OverdueCount() = %d, want 2", n)
+	}
+}
+
+func TestWouldBeOverdue(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	clock.Advance(1500 * time.Millisecond)
+
+	for _, c := range []struct {
+		due  time.Time
+		want bool
+	}{
+		{start.Add(500 * time.Millisecond), true},
+		{start.Add(time.Second), false},
+		{start.Add(3 * time.Second), false},
+	} {
+		if got := s.WouldBeOverdue(c.due); got != c.want {
+			t.Errorf("WouldBeOverdue(%s) = %t, want %t", c.due.Sub(start), got, c.want)
+		}
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d after WouldBeOverdue, want 0", s.Len())
+	}
+}
+
+func TestInCurrentWindow(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	clock.Advance(1500 * time.Millisecond)
+
+	for _, c := range []struct {
+		due  time.Time
+		want bool
+	}{
+		{start.Add(500 * time.Millisecond), false},
+		{start.Add(time.Second), true},
+		{start.Add(1999 * time.Millisecond), true},
+		{start.Add(2 * time.Second), false},
+	} {
+		if got := s.InCurrentWindow(c.due); got != c.want {
+			t.Errorf("InCurrentWindow(%s) = %t, want %t", c.due.Sub(start), got, c.want)
+		}
+	}
+}
+
+func TestExplainPlacement(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	for _, c := range []struct {
+		due  time.Time
+		want string
+	}{
+		{start.Add(-time.Minute), "due time 2022-09-22 10:59:00.000 is before head start 2022-09-22 11:00:00.000, would clamp to head (overdue)"},
+		{start.Add(1500 * time.Millisecond), "due time 2022-09-22 11:00:01.500 is within bucket 1 [2022-09-22 11:00:01.000, 2022-09-22 11:00:02.000)"},
+		{start.Add(time.Hour), "due time 2022-09-22 12:00:00.000 is at or after horizon end 2022-09-22 11:00:04.000, would clamp to tail bucket 3"},
+	} {
+		if got := s.ExplainPlacement(c.due); got != c.want {
+			t.Errorf("ExplainPlacement(%s) = %q, want %q", c.due.Sub(start), got, c.want)
+		}
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d after ExplainPlacement, want 0", s.Len())
+	}
+}
+
+func TestEstimateFireTime(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+	clock.Advance(200 * time.Millisecond)
+
+	for _, c := range []struct {
+		due, want time.Duration
+	}{
+		{-time.Minute, 200 * time.Millisecond},
+		{1500 * time.Millisecond, 2 * time.Second},
+		{time.Hour + 300*time.Millisecond, time.Hour + time.Second},
+	} {
+		if got := s.EstimateFireTime(start.Add(c.due)); !got.Equal(start.Add(c.want)) {
+			t.Errorf("EstimateFireTime(+%s) = +%s, want +%s", c.due, got.Sub(start), c.want)
+		}
+	}
+
+	ticking := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithTickInterval[testItem](100*time.Millisecond),
+	)
+	if got := ticking.EstimateFireTime(start.Add(1500 * time.Millisecond)); !got.Equal(start.Add(1600 * time.Millisecond)) {
+		t.Errorf("EstimateFireTime(+1.5s) with a tick interval = +%s, want +1.6s", got.Sub(start))
+	}
+
+	aligned := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithTickInterval[testItem](100*time.Millisecond),
+		WithFlushAlignment[testItem](5*time.Second),
+	)
+	if got := aligned.EstimateFireTime(start.Add(1500 * time.Millisecond)); !got.Equal(start.Add(5 * time.Second)) {
+		t.Errorf("EstimateFireTime(+1.5s) with flush alignment = +%s, want the +5s boundary", got.Sub(start))
+	}
+
+	precise := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithPreciseTimers[testItem](),
+	)
+	if got := precise.EstimateFireTime(start.Add(1500 * time.Millisecond)); !got.Equal(start.Add(1500 * time.Millisecond)) {
+		t.Errorf("EstimateFireTime(+1.5s) with precise timers = +%s, want +1.5s", got.Sub(start))
+	}
+}
+
+func TestDueHistogram(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock), WithOverflow[testItem]())
+
+	for i, offset := range []time.Duration{
+		-time.Second,            // overdue, counted first
+		0,                       // first half
+		1999 * time.Millisecond, // first half
+		2 * time.Second,         // second half
+		time.Hour,               // beyond the horizon, counted last
+	} {
+		s.AddReminder(testItem{id: string(rune('a' + i)), due: start.Add(offset)})
+	}
+
+	if got := s.DueHistogram(2); !reflect.DeepEqual(got, []int{3, 2}) {
+		t.Errorf("DueHistogram(2) = %v, want [3 2]", got)
+	}
+	if got := s.DueHistogram(8); !reflect.DeepEqual(got, []int{2, 0, 0, 1, 1, 0, 0, 1}) {
+		t.Errorf("DueHistogram(8) = %v, want [2 0 0 1 1 0 0 1]", got)
+	}
+	if got := s.DueHistogram(0); got != nil {
+		t.Errorf("DueHistogram(0) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,49 @@
+package schedule
+
+import "time"
+
+// WithMaxTailDepth bounds the number of items waiting beyond the horizon, in
+// the overflow store or clamped into the last bucket, to n. Once the tail
+// holds n items, adding another item due beyond the horizon fails with
+// ErrTailFull; items within the horizon are still accepted. The default is
+// unbounded.
+func WithMaxTailDepth[T Schedulable](n int) Option[T] {
+	return func(s *Scheduler[T]) {
+		s.maxTailDepth = n
+	}
+}
+
+// TailDepth returns the number of items due beyond the horizon, whether they
+// wait in the overflow store or are clamped into the last bucket.
+func (s *Scheduler[T]) TailDepth() int {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	return s.tailDepth()
+}
+
+func (s *Scheduler[T]) tailDepth() int {
+	if s.overflow != nil {
+		return s.overflow.Size()
+	}
+
+	depth := 0
+	last := s.buckets[len(s.buckets)-1]
+	for _, entity := range last.elements {
+		if s.beyondHorizon(entity.due) {
+			depth++
+		}
+	}
+	return depth
+}
+
+func (s *Scheduler[T]) beyondHorizon(due time.Time) bool {
+	return !s.buckets[len(s.buckets)-1].endTime.After(due)
+}
+
+// tailFull reports whether an item due at due would go beyond the horizon
+// into a tail that is already at WithMaxTailDepth.
+func (s *Scheduler[T]) tailFull(due time.Time) bool {
+	return s.maxTailDepth > 0 && s.beyondHorizon(due) && !s.canGrow(due) && s.tailDepth() >= s.maxTailDepth
+}
@@ -0,0 +1,42 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMaxTailDepth(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	for _, overflow := range []bool{false, true} {
+		opts := []Option[testItem]{WithClock[testItem](clock), WithMaxTailDepth[testItem](3)}
+		if overflow {
+			opts = append(opts, WithOverflow[testItem]())
+		}
+		s := NewScheduler[testItem](context.Background(), time.Second, 4, opts...)
+
+		// Within the horizon, including the last bucket, doesn't count
+		s.AddReminder(testItem{id: "near", due: start.Add(3500 * time.Millisecond)})
+
+		for i, id := range []string{"a", "b", "c"} {
+			if err := s.AddReminder(testItem{id: id, due: start.Add(time.Duration(10+i) * time.Hour)}); err != nil {
+				t.Fatalf("overflow=%v: AddReminder(%s) = %v", overflow, id, err)
+			}
+		}
+		if n := s.TailDepth(); n != 3 {
+			t.Errorf("overflow=%v: TailDepth() = %d, want 3", overflow, n)
+		}
+
+		if err := s.AddReminder(testItem{id: "d", due: start.Add(24 * time.Hour)}); err != ErrTailFull {
+			t.Errorf("overflow=%v: fourth beyond-horizon AddReminder = %v, want ErrTailFull", overflow, err)
+		}
+		if err := s.AddReminder(testItem{id: "e", due: start.Add(2 * time.Second)}); err != nil {
+			t.Errorf("overflow=%v: AddReminder within the horizon = %v, want nil", overflow, err)
+		}
+		if n := s.Len(); n != 5 {
+			t.Errorf("overflow=%v: Len() = %d, want 5", overflow, n)
+		}
+	}
+}
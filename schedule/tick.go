@@ -0,0 +1,18 @@
+package schedule
+
+// Tick steps the scheduler forward by one blockSize, advancing its clock if it
+// can be advanced by hand (as a FakeClock can), and returns everything that is
+// now due. It is meant for schedulers driven by an external loop and must not
+// be mixed with Start. With a clock that can't be advanced it simply collects
+// whatever is due.
+func (s *Scheduler[T]) Tick() []T {
+	if clock, ok := s.clock.(advancer); ok {
+		clock.Advance(s.resolution())
+	}
+
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	return items(s.takeDue(-1))
+}
@@ -0,0 +1,59 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTick(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(2500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "c", due: start.Add(3 * time.Second)})
+
+	want := []string{"[a]", "[]", "[b c]"}
+	for i, w := range want {
+		var ids []string
+		for _, item := range s.Tick() {
+			ids = append(ids, item.id)
+		}
+		if got := "[" + strings.Join(ids, " ") + "]"; got != w {
+			t.Errorf("tick %d returned %s, want %s", i+1, got, w)
+		}
+	}
+
+	if now := clock.Now(); !now.Equal(start.Add(3 * time.Second)) {
+		t.Errorf("clock is at %s after three ticks, want %s", now, start.Add(3*time.Second))
+	}
+}
+
+func TestOnTick(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	var s *Scheduler[testItem]
+	var ticks []string
+	s = NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithOnTick[testItem](func(now time.Time, depth int) {
+			// Called outside the lock, so the scheduler can be used here
+			ticks = append(ticks, fmt.Sprintf("%s:%d:%d", now.Sub(start), depth, s.Len()))
+		}),
+	)
+
+	s.AddReminder(testItem{id: "a", due: start.Add(1500 * time.Millisecond)})
+	s.Tick()
+	s.Tick()
+	s.Tick()
+	s.Len()
+
+	if want := "[1s:1:1 2s:1:0 3s:0:0]"; fmt.Sprint(ticks) != want {
+		t.Errorf("ticks = %v, want %s", ticks, want)
+	}
+}
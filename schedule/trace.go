@@ -0,0 +1,131 @@
+package schedule
+
+import "time"
+
+type TraceKind int
+
+const (
+	TraceAdded TraceKind = iota
+	// TraceMoved items had their due time changed, e.g. by Defer or Shift.
+	TraceMoved
+	// TraceRotated items were still waiting when their bucket's window ended
+	// and were carried into the head bucket as overdue.
+	TraceRotated
+	TraceFired
+	TraceCancelled
+	TraceExpired
+	// TraceDropped items were later than WithMaxLateness allows.
+	TraceDropped
+)
+
+func (k TraceKind) String() string {
+	switch k {
+	case TraceAdded:
+		return "added"
+	case TraceMoved:
+		return "moved"
+	case TraceRotated:
+		return "rotated"
+	case TraceFired:
+		return "fired"
+	case TraceCancelled:
+		return "cancelled"
+	case TraceExpired:
+		return "expired"
+	case TraceDropped:
+		return "dropped"
+	default:
+		return "unknown"
+	}
+}
+
+// TraceEvent is one step in the life of a traced item.
+type TraceEvent struct {
+	Kind TraceKind
+	Id   string
+	// Due is the item's due time after the event.
+	Due time.Time
+	// At is the scheduler's time when it happened.
+	At time.Time
+}
+
+const traceBuffer = 64
+
+// Trace returns a feed of everything that happens to items with the given
+// Id, for following one item through the scheduler. It may be called before
+// the item is added. The channel is closed once nothing with that Id is left
+// scheduled after it fires for the last time or is cancelled, expired or
+// dropped, or once the scheduler's context is done. It buffers traceBuffer
+// events; if the consumer falls further behind than that, further events are
+// dropped rather than blocking the scheduler.
+func (s *Scheduler[T]) Trace(id string) <-chan TraceEvent {
+	s.mutex.Lock()
+	defer s.unlock()
+
+	ch := make(chan TraceEvent, traceBuffer)
+	if s.tracesClosed {
+		close(ch)
+		return ch
+	}
+
+	if s.traces == nil {
+		s.traces = make(map[string][]chan TraceEvent)
+		if done := s.ctx.Done(); done != nil {
+			go func() {
+				<-done
+				s.mutex.Lock()
+				defer s.mutex.Unlock()
+				for id := range s.traces {
+					s.endTrace(id)
+				}
+				s.tracesClosed = true
+			}()
+		}
+	}
+	s.traces[id] = append(s.traces[id], ch)
+
+	return ch
+}
+
+// trace sends an event to anyone tracing entity and ends the trace if the
+// event took the last item with its Id out of the scheduler.
+func (s *Scheduler[T]) trace(kind TraceKind, entity *entry[T]) {
+	if len(s.traces) == 0 {
+		return
+	}
+	chans, ok := s.traces[entity.id]
+	if !ok {
+		return
+	}
+
+	event := TraceEvent{Kind: kind, Id: entity.id, Due: entity.due, At: s.now()}
+	for _, ch := range chans {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+
+	switch kind {
+	case TraceCancelled:
+		if !s.replacing {
+			s.traceLeft(entity.id)
+		}
+	case TraceFired, TraceExpired, TraceDropped:
+		s.traceLeft(entity.id)
+	}
+}
+
+// traceLeft ends the trace for id if nothing with that Id is left scheduled.
+func (s *Scheduler[T]) traceLeft(id string) {
+	if _, ok := s.traces[id]; ok && s.find(id) == nil {
+		s.endTrace(id)
+	}
+}
+
+func (s *Scheduler[T]) endTrace(id string) {
+	for _, ch := range s.traces[id] {
+		close(ch)
+	}
+	delete(s.traces, id)
+}
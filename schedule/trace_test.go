@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTrace(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	trace := s.Trace("x")
+	s.AddReminder(testItem{id: "x", due: start.Add(1500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "other", due: start.Add(1600 * time.Millisecond)})
+	s.Defer(1, time.Second)
+
+	// x is still waiting when its bucket's window ends
+	clock.Advance(3200 * time.Millisecond)
+	s.NextN(1)
+	if due := s.Due(); len(due) != 2 {
+		t.Fatalf("Due() = %v, want x and other", due)
+	}
+
+	var kinds []TraceKind
+	for event := range trace {
+		if event.Id != "x" {
+			t.Errorf("traced event for %s", event.Id)
+		}
+		kinds = append(kinds, event.Kind)
+	}
+	if want := []TraceKind{TraceAdded, TraceMoved, TraceRotated, TraceFired}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("traced %v, want %v", kinds, want)
+	}
+}
+
+func TestTraceCancelled(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[recurringItem](context.Background(), time.Second, 4, WithClock[recurringItem](clock))
+
+	s.AddReminder(recurringItem{testItem: testItem{id: "r", due: start.Add(500 * time.Millisecond)}, interval: time.Second})
+	trace := s.Trace("r")
+
+	clock.Advance(time.Second)
+	s.Due()
+	s.Cancel("r")
+
+	var events []TraceEvent
+	for event := range trace {
+		events = append(events, event)
+	}
+	if len(events) != 3 || events[0].Kind != TraceRotated || events[1].Kind != TraceFired || events[2].Kind != TraceCancelled {
+		t.Fatalf("traced %v, want rotated, fired then cancelled", events)
+	}
+	if want := start.Add(1500 * time.Millisecond); !events[1].Due.Equal(want) {
+		t.Errorf("fired event due %s, want the next occurrence at %s", events[1].Due, want)
+	}
+}
+
+func TestTraceReplaced(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock),
+		WithDedupe[testItem](DedupeReplace),
+	)
+
+	trace := s.Trace("x")
+	s.AddReminder(testItem{id: "x", due: start.Add(500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "x", due: start.Add(2500 * time.Millisecond)})
+	if !s.Has("x") {
+		t.Fatal("Has(x) = false after replacing it")
+	}
+
+	// The replacement carries on the trace until it really leaves
+	var kinds []TraceKind
+	for len(kinds) < 3 {
+		event, ok := <-trace
+		if !ok {
+			t.Fatalf("trace closed after %v with x still scheduled", kinds)
+		}
+		kinds = append(kinds, event.Kind)
+	}
+	s.Cancel("x")
+	for event := range trace {
+		kinds = append(kinds, event.Kind)
+	}
+	if want := []TraceKind{TraceAdded, TraceCancelled, TraceAdded, TraceCancelled}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("traced %v, want %v", kinds, want)
+	}
+}
@@ -0,0 +1,91 @@
+package schedule
+
+// Tx stages adds and cancellations so they can be applied together, or not
+// at all, once the caller knows whether its own transaction went through.
+// Nothing staged is visible to the scheduler until Commit. A Tx is not safe
+// for concurrent use.
+type Tx[T Schedulable] struct {
+	s    *Scheduler[T]
+	ops  []txOp[T]
+	done bool
+}
+
+// txOp is a staged Add, or a Cancel of id if remove is set.
+type txOp[T Schedulable] struct {
+	item   T
+	id     string
+	remove bool
+}
+
+// Begin starts a transaction against the scheduler.
+func (s *Scheduler[T]) Begin() *Tx[T] {
+	return &Tx[T]{s: s}
+}
+
+// Add stages item to be scheduled on Commit.
+func (tx *Tx[T]) Add(item T) {
+	tx.ops = append(tx.ops, txOp[T]{item: item})
+}
+
+// Cancel stages the removal, as Scheduler.Cancel, of every item with the
+// given Id on Commit, including any added earlier in the transaction.
+func (tx *Tx[T]) Cancel(id string) {
+	tx.ops = append(tx.ops, txOp[T]{id: id, remove: true})
+}
+
+// Commit applies the staged operations in order under a single acquisition
+// of the scheduler's lock, so other callers see either none or all of them.
+// If the scheduler would refuse any staged add, as AddReminder would, nothing
+// is applied and the first such error is returned. Committing a transaction
+// that is already done returns ErrTxDone.
+func (tx *Tx[T]) Commit() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+
+	s := tx.s
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	// Try the operations out on a copy first, since whether an add is
+	// refused can depend on the ones before it. The copy's callbacks are
+	// only queued to run on unlock, which never happens, and it mustn't
+	// call the window loader for windows s is yet to load.
+	trial := s.clone()
+	trial.loader = nil
+	trial.logger = nopLogger{}
+	if err := tx.apply(trial); err != nil {
+		tx.ops = nil
+		return err
+	}
+
+	tx.apply(s)
+	s.signalIfEmpty()
+	tx.ops = nil
+
+	return nil
+}
+
+// apply applies the staged operations to s, stopping at the first add it
+// refuses.
+func (tx *Tx[T]) apply(s *Scheduler[T]) error {
+	for _, op := range tx.ops {
+		if op.remove {
+			s.cancelId(op.id)
+			continue
+		}
+		if _, err := s.tryAdd(newEntry(op.item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback discards the staged operations. Rolling back a transaction that
+// is already done does nothing.
+func (tx *Tx[T]) Rollback() {
+	tx.done = true
+	tx.ops = nil
+}
@@ -0,0 +1,113 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTxRollback(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+	s.AddReminder(testItem{id: "a", due: start.Add(500 * time.Millisecond)})
+
+	tx := s.Begin()
+	tx.Add(testItem{id: "b", due: start.Add(600 * time.Millisecond)})
+	tx.Cancel("a")
+
+	if n := s.Len(); n != 1 {
+		t.Errorf("Len() with a transaction open = %d, want 1", n)
+	}
+
+	tx.Rollback()
+	if err := tx.Commit(); err != ErrTxDone {
+		t.Errorf("Commit() after Rollback = %v, want ErrTxDone", err)
+	}
+
+	clock.Advance(time.Second)
+	if due := s.Due(); len(due) != 1 || due[0].id != "a" {
+		t.Errorf("Due() = %v, want just a", due)
+	}
+}
+
+func TestTxCommit(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](clock), WithDedupe[testItem](DedupeReject))
+	s.AddReminder(testItem{id: "a", due: start.Add(2 * time.Second)})
+
+	tx := s.Begin()
+	tx.Add(testItem{id: "b", due: start.Add(600 * time.Millisecond)})
+	tx.Add(testItem{id: "c", due: start.Add(700 * time.Millisecond)})
+	tx.Add(testItem{id: "a", due: start.Add(800 * time.Millisecond)})
+	tx.Cancel("c")
+	tx.Add(testItem{id: "d", due: start.Add(900 * time.Millisecond)})
+
+	clock.Advance(time.Second)
+	if due := s.Due(); len(due) != 0 {
+		t.Fatalf("Due() before Commit = %v, want nothing", due)
+	}
+
+	if err := tx.Commit(); err != ErrDuplicateId {
+		t.Errorf("Commit() = %v, want ErrDuplicateId for the second a", err)
+	}
+	if err := tx.Commit(); err != ErrTxDone {
+		t.Errorf("second Commit() = %v, want ErrTxDone", err)
+	}
+
+	// The refused add means none of it is applied
+	if due := s.Due(); len(due) != 0 {
+		t.Errorf("Due() after a refused Commit = %v, want nothing", due)
+	}
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d after a refused Commit, want 1", s.Len())
+	}
+
+	tx = s.Begin()
+	tx.Add(testItem{id: "b", due: start.Add(600 * time.Millisecond)})
+	tx.Add(testItem{id: "c", due: start.Add(700 * time.Millisecond)})
+	tx.Cancel("c")
+	tx.Add(testItem{id: "d", due: start.Add(900 * time.Millisecond)})
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v", err)
+	}
+
+	due := s.Due()
+	if len(due) != 2 || due[0].id != "b" || due[1].id != "d" {
+		t.Errorf("Due() after Commit = %v, want [b d]", due)
+	}
+}
+
+func TestTxCommitDependsOnEarlierOps(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4,
+		WithClock[testItem](NewFakeClock(start)), WithCapacity[testItem](2))
+	s.AddReminder(testItem{id: "a", due: start.Add(time.Second)})
+
+	// b fits only once a has gone, and c never does
+	tx := s.Begin()
+	tx.Cancel("a")
+	tx.Add(testItem{id: "b", due: start.Add(time.Second)})
+	tx.Add(testItem{id: "c", due: start.Add(time.Second)})
+	tx.Add(testItem{id: "d", due: start.Add(time.Second)})
+	if err := tx.Commit(); err != ErrCapacity {
+		t.Fatalf("Commit() = %v, want ErrCapacity", err)
+	}
+	if !s.Has("a") || s.Has("b") || s.Len() != 1 || s.TotalCancelled() != 0 {
+		t.Errorf("after a refused Commit Has(a) = %v, Has(b) = %v, Len() = %d, TotalCancelled() = %d, want only a untouched",
+			s.Has("a"), s.Has("b"), s.Len(), s.TotalCancelled())
+	}
+
+	tx = s.Begin()
+	tx.Cancel("a")
+	tx.Add(testItem{id: "b", due: start.Add(time.Second)})
+	tx.Add(testItem{id: "c", due: start.Add(time.Second)})
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v", err)
+	}
+	if s.Has("a") || s.Len() != 2 {
+		t.Errorf("after Commit Has(a) = %v and Len() = %d, want b and c only", s.Has("a"), s.Len())
+	}
+}
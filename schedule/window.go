@@ -0,0 +1,37 @@
+package schedule
+
+import "time"
+
+// DueByWindow removes and returns everything that is due, like Due, keyed by
+// the [start, end) window of the bucket each item was scheduled into. Items
+// that have since been merged into the head bucket are still keyed by their
+// original window. Keys are built from the bucket boundaries with any
+// monotonic clock reading stripped, so equal windows always compare equal with
+// ==; within a window items keep the order Due would return them in.
+func (s *Scheduler[T]) DueByWindow() map[[2]time.Time][]T {
+	s.mutex.Lock()
+	defer s.unlock()
+	s.update()
+
+	windows := make(map[[2]time.Time][]T)
+	for _, entity := range s.takeDue(-1) {
+		window := s.windowOf(entity.due)
+		windows[window] = append(windows[window], entity.item)
+	}
+	return windows
+}
+
+// windowOf returns the bucket window, aligned to the current buckets, that
+// contains due, whether or not a bucket for it still exists.
+func (s *Scheduler[T]) windowOf(due time.Time) [2]time.Time {
+	base := s.buckets[0].startTime.Round(0)
+
+	offset := due.Sub(base)
+	blocks := offset / s.blockSize
+	if offset < 0 && offset%s.blockSize != 0 {
+		blocks--
+	}
+
+	start := base.Add(blocks * s.blockSize)
+	return [2]time.Time{start, start.Add(s.blockSize)}
+}
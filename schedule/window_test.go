@@ -0,0 +1,37 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDueByWindow(t *testing.T) {
+	start := time.Date(2022, 9, 22, 11, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler[testItem](context.Background(), time.Second, 4, WithClock[testItem](clock))
+
+	s.AddReminder(testItem{id: "a", due: start.Add(200 * time.Millisecond)})
+	s.AddReminder(testItem{id: "b", due: start.Add(700 * time.Millisecond)})
+	s.AddReminder(testItem{id: "c", due: start.Add(1500 * time.Millisecond)})
+	s.AddReminder(testItem{id: "d", due: start.Add(3500 * time.Millisecond)})
+
+	clock.Advance(2 * time.Second)
+	windows := s.DueByWindow()
+
+	window := func(from int) [2]time.Time {
+		return [2]time.Time{start.Add(time.Duration(from) * time.Second), start.Add(time.Duration(from+1) * time.Second)}
+	}
+	if len(windows) != 2 {
+		t.Fatalf("DueByWindow() returned %d windows, want 2: %v", len(windows), windows)
+	}
+	if got := windows[window(0)]; len(got) != 2 || got[0].id != "a" || got[1].id != "b" {
+		t.Errorf("window [0s, 1s) = %v, want [a b]", got)
+	}
+	if got := windows[window(1)]; len(got) != 1 || got[0].id != "c" {
+		t.Errorf("window [1s, 2s) = %v, want [c]", got)
+	}
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", s.Len())
+	}
+}